@@ -0,0 +1,117 @@
+package biosim
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+type (
+	// MutationSchedule decides how the global mutation rate changes during a run
+	MutationSchedule uint8
+
+	// rateAdapter keeps track of how the population is doing, and turns the mutation rate up when
+	// it gets stuck, and down when it is making progress
+	rateAdapter struct {
+		best     int // the most survivors seen so far
+		stagnant int // the number of generations since best was beaten
+	}
+)
+
+const (
+	// MutationFixed keeps the mutation rate where it is, unless it is changed through the http api
+	MutationFixed MutationSchedule = iota
+
+	// MutationAdaptive raises the mutation rate when the number of survivors stops improving or the
+	// diversity of the population collapses, and lowers it while things are getting better
+	MutationAdaptive
+)
+
+const (
+	ADAPT_WINDOW      = 5   // generations without a new best before the population counts as stuck
+	ADAPT_UP          = 1.2 // the mutation rate is multiplied by this when stuck
+	ADAPT_DOWN        = 0.9 // and by this when improving
+	MIN_DIVERSITY     = 0.1 // share of distinct genomes below which diversity has collapsed
+	MIN_MUTATION_RATE = 1
+	MAX_MUTATION_RATE = 1000
+)
+
+var mutationScheduleNames = map[MutationSchedule]string{
+	MutationFixed:    "fixed",
+	MutationAdaptive: "adaptive",
+}
+
+func (m MutationSchedule) String() string {
+	return mutationScheduleNames[m]
+}
+
+// Set implements flag.Value
+func (m *MutationSchedule) Set(s string) error {
+	for schedule, name := range mutationScheduleNames {
+		if name == s {
+			*m = schedule
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown mutation schedule %q", s)
+}
+
+// adapt returns the mutation rate to use for the next generation
+func (a *rateAdapter) adapt(rate int64, survivors int, diversity float64) int64 {
+	factor := 1.0
+	switch {
+	case survivors > a.best:
+		a.best = survivors
+		a.stagnant = 0
+		factor = ADAPT_DOWN
+	case diversity < MIN_DIVERSITY:
+		factor = ADAPT_UP
+	default:
+		a.stagnant++
+		if a.stagnant >= ADAPT_WINDOW {
+			a.stagnant = 0
+			factor = ADAPT_UP
+		}
+	}
+
+	next := int64(math.Round(float64(rate) * factor))
+	// small rates would never move when rounded, so they always take at least one step
+	switch {
+	case factor > 1 && next == rate:
+		next++
+	case factor < 1 && next == rate:
+		next--
+	}
+	if next < MIN_MUTATION_RATE {
+		next = MIN_MUTATION_RATE
+	}
+	if next > MAX_MUTATION_RATE {
+		next = MAX_MUTATION_RATE
+	}
+	return next
+}
+
+// diversity is the number of distinct genomes, as a share of the number of individuals
+func diversity(peeps []*world.Individual) float64 {
+	if len(peeps) == 0 {
+		return 0
+	}
+	seen := map[string]bool{}
+	for _, peep := range peeps {
+		seen[fmt.Sprint(peep.Genome.NoOfNeurons, peep.Genome.Genes)] = true
+	}
+	return float64(len(seen)) / float64(len(peeps))
+}
+
+// meanMutationBias returns the average mutation bias of the genomes, see genome.Genome.MutationBias
+func meanMutationBias(peeps []*world.Individual) float64 {
+	if len(peeps) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, peep := range peeps {
+		sum += peep.Genome.MutationBias
+	}
+	return sum / float64(len(peeps))
+}
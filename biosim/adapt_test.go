@@ -0,0 +1,36 @@
+package biosim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+func TestRateAdapter(t *testing.T) {
+	a := &rateAdapter{}
+
+	// improving lowers the rate
+	assert.EqualValues(t, 90, a.adapt(100, 10, 1))
+	assert.EqualValues(t, 1, a.adapt(1, 20, 1))
+
+	// stagnating raises it, but only after a while
+	rate := int64(100)
+	for i := 1; i < ADAPT_WINDOW; i++ {
+		rate = a.adapt(rate, 20, 1)
+		assert.EqualValues(t, 100, rate)
+	}
+	assert.EqualValues(t, 120, a.adapt(rate, 20, 1))
+
+	// a population of clones raises it right away
+	assert.EqualValues(t, 120, a.adapt(100, 5, 0))
+	assert.EqualValues(t, MAX_MUTATION_RATE, a.adapt(MAX_MUTATION_RATE, 5, 0))
+}
+
+func TestDiversity(t *testing.T) {
+	g := genome.MakeRandomGenome(5, rnd)
+	peeps := []*world.Individual{{Genome: g}, {Genome: g}, {Genome: genome.MakeRandomGenome(6, rnd)}, {Genome: genome.MakeRandomGenome(7, rnd)}}
+	assert.Equal(t, 0.75, diversity(peeps))
+	assert.Equal(t, 0.0, diversity(nil))
+}
@@ -0,0 +1,182 @@
+package brain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type (
+	// jsonNet is the shape a NeuralNet has when exported as JSON
+	jsonNet struct {
+		Sensors     []string         `json:"sensors"`
+		Neurons     []string         `json:"neurons"`
+		Actions     []string         `json:"actions"`
+		Connections []jsonConnection `json:"connections"`
+	}
+
+	jsonConnection struct {
+		From   string  `json:"from"`
+		To     string  `json:"to"`
+		Weight float64 `json:"weight"`
+	}
+
+	// jsonGraph is the shape a Graph has when exported as JSON
+	jsonGraph struct {
+		Nodes    []jsonNode   `json:"nodes"`
+		Vertices []jsonVertix `json:"vertices"`
+	}
+
+	jsonNode struct {
+		ID    int    `json:"id"`
+		Label string `json:"label"`
+	}
+
+	jsonVertix struct {
+		From int         `json:"from"`
+		To   int         `json:"to"`
+		Data interface{} `json:"data,omitempty"`
+	}
+)
+
+func (s SensorInput) name() string { return s.s.String() }
+func (n *Neuron) name() string     { return fmt.Sprintf("N%d", n.id) }
+func (n ActionSink) name() string  { return n.action.String() }
+
+// actions returns the actions the net is connected to, in the order they are first used
+func (n *NeuralNet) actions() (result []Action) {
+	seen := map[Action]bool{}
+	for _, conn := range n.Connections {
+		if sink, ok := conn.To.(ActionSink); ok && !seen[sink.action] {
+			seen[sink.action] = true
+			result = append(result, sink.action)
+		}
+	}
+	return
+}
+
+// weightColor picks a color for a connection weight. Positive weights are green, negative are red,
+// and the stronger the weight, the stronger the color
+func weightColor(w float64) string {
+	strength := math.Min(math.Abs(w), 1)
+	shade := uint8(200 - 200*strength)
+	if w < 0 {
+		return fmt.Sprintf("#ff%02x%02x", shade, shade)
+	}
+	return fmt.Sprintf("#%02xc0%02x", shade, shade)
+}
+
+// DOT renders the net in the Graphviz dot format. Sensors are drawn as boxes on the top,
+// actions as double circles on the bottom, and neurons as circles in between
+func (n *NeuralNet) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph brain {\n")
+	sb.WriteString("\tnode [fontname=\"Helvetica\"];\n")
+
+	sb.WriteString("\t{ rank=source;\n")
+	for _, sensor := range n.Sensors {
+		fmt.Fprintf(&sb, "\t\t%q [shape=box, style=filled, fillcolor=\"#bfd7ff\"];\n", sensor.String())
+	}
+	sb.WriteString("\t}\n")
+
+	for _, neuron := range n.Neurons {
+		if neuron != nil {
+			fmt.Fprintf(&sb, "\t%q [shape=circle, style=filled, fillcolor=\"#eeeeee\"];\n", neuron.name())
+		}
+	}
+
+	sb.WriteString("\t{ rank=sink;\n")
+	for _, action := range n.actions() {
+		fmt.Fprintf(&sb, "\t\t%q [shape=doublecircle, style=filled, fillcolor=\"#ffe0a0\"];\n", action.String())
+	}
+	sb.WriteString("\t}\n")
+
+	for _, conn := range n.Connections {
+		fmt.Fprintf(&sb, "\t%q -> %q [label=\"%.3f\", color=\"%s\", penwidth=%.2f];\n",
+			conn.From.name(), conn.To.name(), conn.multiplier, weightColor(conn.multiplier),
+			1+2*math.Min(math.Abs(conn.multiplier), 1))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (n *NeuralNet) MarshalJSON() ([]byte, error) {
+	result := jsonNet{
+		Sensors:     []string{},
+		Neurons:     []string{},
+		Actions:     []string{},
+		Connections: []jsonConnection{},
+	}
+	for _, sensor := range n.Sensors {
+		result.Sensors = append(result.Sensors, sensor.String())
+	}
+	for _, neuron := range n.Neurons {
+		if neuron != nil {
+			result.Neurons = append(result.Neurons, neuron.name())
+		}
+	}
+	for _, action := range n.actions() {
+		result.Actions = append(result.Actions, action.String())
+	}
+	for _, conn := range n.Connections {
+		result.Connections = append(result.Connections, jsonConnection{
+			From:   conn.From.name(),
+			To:     conn.To.name(),
+			Weight: conn.multiplier,
+		})
+	}
+	return json.Marshal(result)
+}
+
+// DOT renders the graph in the Graphviz dot format. Nodes are labeled with their value,
+// and vertices with a float64 value are labeled and colored by it
+func (g *Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph G {\n")
+	for id, node := range g.nodes {
+		if node == nil {
+			continue
+		}
+		shape := "circle"
+		switch node.(type) {
+		case Sensor:
+			shape = "box"
+		case Action:
+			shape = "doublecircle"
+		}
+		fmt.Fprintf(&sb, "\t%d [label=%q, shape=%s];\n", id, fmt.Sprintf("%v", node), shape)
+	}
+
+	for from := 0; from < g.size; from++ {
+		for _, to := range g.NeighboursFrom(from) {
+			v := g.GetVertix(from, to).(Vertix)
+			if w, ok := v.Data.(float64); ok {
+				fmt.Fprintf(&sb, "\t%d -> %d [label=\"%.3f\", color=\"%s\"];\n", from, to, w, weightColor(w))
+			} else {
+				fmt.Fprintf(&sb, "\t%d -> %d;\n", from, to)
+			}
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	result := jsonGraph{
+		Nodes:    []jsonNode{},
+		Vertices: []jsonVertix{},
+	}
+	for id, node := range g.nodes {
+		if node != nil {
+			result.Nodes = append(result.Nodes, jsonNode{ID: id, Label: fmt.Sprintf("%v", node)})
+		}
+	}
+	for from := 0; from < g.size; from++ {
+		for _, to := range g.NeighboursFrom(from) {
+			v := g.GetVertix(from, to).(Vertix)
+			result.Vertices = append(result.Vertices, jsonVertix{From: from, To: to, Data: v.Data})
+		}
+	}
+	return json.Marshal(result)
+}
@@ -0,0 +1,55 @@
+package brain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeuralNet_DOT(t *testing.T) {
+	dot := loopingBrain().DOT()
+
+	assert.Contains(t, dot, `"BLOCK" [shape=box`)
+	assert.Contains(t, dot, `"N0" [shape=circle`)
+	assert.Contains(t, dot, `"MOVE_X" [shape=doublecircle`)
+	assert.Contains(t, dot, `"BLOCK" -> "N0" [label="1.500"`)
+	assert.Contains(t, dot, `"N0" -> "MOVE_X" [label="0.500"`)
+}
+
+func TestNeuralNet_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(loopingBrain())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"sensors": ["BLOCK"],
+		"neurons": ["N0"],
+		"actions": ["MOVE_X"],
+		"connections": [
+			{"from": "BLOCK", "to": "N0", "weight": 1.5},
+			{"from": "N0", "to": "N0", "weight": 2},
+			{"from": "N0", "to": "MOVE_X", "weight": 0.5}
+		]
+	}`, string(data))
+}
+
+func TestGraph_Export(t *testing.T) {
+	g := NewGraph(3)
+	addNode(t, g, 0, "node 0")
+	addNode(t, g, 1, "node 1")
+	require.NoError(t, g.AddVertix(0, 1, -0.25))
+
+	assert.Equal(t, "digraph G {\n"+
+		"\t0 [label=\"node 0\", shape=circle];\n"+
+		"\t1 [label=\"node 1\", shape=circle];\n"+
+		"\t0 -> 1 [label=\"-0.250\", color=\"#ff9696\"];\n"+
+		"}\n", g.DOT())
+
+	data, err := json.Marshal(g)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"nodes": [{"id": 0, "label": "node 0"}, {"id": 1, "label": "node 1"}],
+		"vertices": [{"from": 0, "to": 1, "data": -0.25}]
+	}`, string(data))
+}
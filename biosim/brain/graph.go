@@ -1,11 +1,11 @@
-package main
+package brain
 
 import "fmt"
 
 type (
 	Vertix struct {
-		from, to int
-		data     interface{}
+		From, To int
+		Data     interface{}
 	}
 	Graph struct {
 		// this is the number of nodes that the graph can contain
@@ -36,6 +36,11 @@ func NewGraph(size int) *Graph {
 	}
 }
 
+// Size is the number of nodes the graph can contain
+func (g *Graph) Size() int {
+	return g.size
+}
+
 func (g *Graph) AddNode(i int, node interface{}) error {
 	if g.size < i {
 		return fmt.Errorf("node too large")
@@ -69,9 +74,9 @@ func (g *Graph) AddVertix(from, to int, data interface{}) error {
 		return nil
 	}
 	g.vertices = append(g.vertices, Vertix{
-		from: from,
-		to:   to,
-		data: data,
+		From: from,
+		To:   to,
+		Data: data,
 	})
 	g.matrix[idx] = len(g.vertices)
 	return nil
@@ -86,6 +91,54 @@ func (g *Graph) NeighboursFrom(from int) (result []int) {
 	return
 }
 
+func (g *Graph) NeighboursTo(to int) (result []int) {
+	for from := 0; from < g.size; from++ {
+		if g.matrix[g.matrixOffset(from, to)] != 0 {
+			result = append(result, from)
+		}
+	}
+	return
+}
+
+// UsefulVertices returns the vertices that lie on a path from one of the sources to one of the sinks.
+// A vertix is useful when its from-node can be reached from a source, and its to-node can reach a sink.
+// This is linear in the size of the graph, unlike PathsBetween which enumerates every path.
+// For graphs without loops, the result is the same set of vertices that PathsBetween visits. When there
+// are loops, vertices that lead back into a path are also returned, since signals can travel around a loop.
+func (g *Graph) UsefulVertices(sources, sinks []int) (result []Vertix) {
+	forward := g.reachable(sources, g.NeighboursFrom)
+	backward := g.reachable(sinks, g.NeighboursTo)
+	for _, v := range g.vertices {
+		if forward[v.From] && backward[v.To] {
+			result = append(result, v)
+		}
+	}
+	return
+}
+
+// reachable does a breadth first search from the start nodes and marks every node it can reach
+func (g *Graph) reachable(start []int, neighbours func(int) []int) []bool {
+	seen := make([]bool, g.size)
+	todo := make([]int, 0, len(start))
+	for _, node := range start {
+		if !seen[node] {
+			seen[node] = true
+			todo = append(todo, node)
+		}
+	}
+	for len(todo) > 0 {
+		current := todo[0]
+		todo = todo[1:]
+		for _, other := range neighbours(current) {
+			if !seen[other] {
+				seen[other] = true
+				todo = append(todo, other)
+			}
+		}
+	}
+	return seen
+}
+
 func (g *Graph) createVertixPath(ints intPath) (result []Vertix) {
 	last := -1
 	for _, node := range ints {
@@ -107,8 +160,10 @@ func (i intPath) contains(node int) bool {
 	return false
 }
 
+// PathsBetween enumerates every path without loops from the from-nodes to the to-nodes.
+// The number of paths grows exponentially with the density of the graph, so for finding
+// out which vertices are used by any path, use UsefulVertices instead
 func (g *Graph) PathsBetween(from, to []int) (result []Path) {
-	// TODO: lots of optimization possibilities here
 	var todo []intPath
 	for _, node := range from {
 		todo = append(todo, intPath{node})
@@ -124,7 +179,8 @@ func (g *Graph) PathsBetween(from, to []int) (result []Path) {
 				// we don't want loops
 				continue
 			}
-			thisPath := append(current, otherNode)
+			// copy the path, so paths that share a prefix don't share their backing array
+			thisPath := append(append(intPath(nil), current...), otherNode)
 			for _, dst := range to {
 				if otherNode == dst {
 					// we found a path!
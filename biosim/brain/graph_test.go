@@ -0,0 +1,159 @@
+package brain
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestName(t *testing.T) {
+
+	g := NewGraph(3)
+	require.NoError(t,
+		g.AddNode(0, "node 0"))
+	require.NoError(t,
+		g.AddNode(1, "node 1"))
+	require.NoError(t,
+		g.AddNode(2, "node 2"))
+
+	require.NoError(t,
+		g.AddVertix(0, 1, "0->1"))
+	require.NoError(t,
+		g.AddVertix(1, 2, "1->2"))
+
+	apa := g.PathsBetween([]int{0}, []int{2})
+	fmt.Printf("%v\n", apa)
+}
+
+func TestBigGraph(t *testing.T) {
+	g := NewGraph(9)
+	addNode(t, g, 0, "node 0")
+	addNode(t, g, 1, "node 1")
+	addNode(t, g, 2, "node 2")
+	addNode(t, g, 3, "node 3")
+	addNode(t, g, 4, "node 4")
+	addNode(t, g, 5, "node 5")
+	addNode(t, g, 6, "node 6")
+	addNode(t, g, 7, "node 7")
+	addNode(t, g, 8, "node 8")
+
+	addV(t, g, 0, 3)
+	addV(t, g, 0, 4)
+	addV(t, g, 1, 4)
+	addV(t, g, 4, 1)
+	addV(t, g, 2, 5)
+	addV(t, g, 4, 7)
+	addV(t, g, 4, 8)
+
+	paths := g.PathsBetween([]int{0, 1, 2}, []int{6, 7, 8})
+
+	fmt.Printf("%v\n", paths)
+}
+
+func addNode(t testing.TB, g *Graph, i int, node string) {
+	require.NoError(t,
+		g.AddNode(i, node))
+}
+func addV(t testing.TB, g *Graph, from, to int) {
+	require.NoError(t,
+		g.AddVertix(from, to, ""))
+}
+
+func TestNeigbour(t *testing.T) {
+	g := NewGraph(3)
+	require.NoError(t,
+		g.AddNode(0, "node 0"))
+	require.NoError(t,
+		g.AddNode(1, "node 1"))
+	require.NoError(t,
+		g.AddNode(2, "node 2"))
+
+	require.NoError(t,
+		g.AddVertix(0, 1, "0->1"))
+	require.NoError(t,
+		g.AddVertix(0, 2, "0->2"))
+
+	assert.Equal(t, []int{1, 2}, g.NeighboursFrom(0))
+	assert.Empty(t, g.NeighboursFrom(1))
+}
+
+// vertixSet returns the from->to pairs of the vertices, so that results can be compared regardless of order
+func vertixSet(vertices []Vertix) map[[2]int]bool {
+	result := map[[2]int]bool{}
+	for _, v := range vertices {
+		result[[2]int{v.From, v.To}] = true
+	}
+	return result
+}
+
+// randomAcyclicGraph creates a graph where the first nodes are sources and the last nodes are sinks,
+// and vertices only go from a lower to a higher node, so there can be no loops
+func randomAcyclicGraph(t testing.TB, rnd *rand.Rand, size, sources, sinks, vertices int) *Graph {
+	g := NewGraph(size)
+	for i := 0; i < size; i++ {
+		addNode(t, g, i, fmt.Sprintf("node %d", i))
+	}
+	for i := 0; i < vertices; i++ {
+		from := rnd.Intn(size - sinks)
+		to := sources + rnd.Intn(size-sources)
+		if from >= to {
+			continue
+		}
+		addV(t, g, from, to)
+	}
+	return g
+}
+
+func TestUsefulVerticesSameAsPathsBetween(t *testing.T) {
+	rnd := rand.New(rand.NewSource(42))
+	for i := 0; i < 200; i++ {
+		size := 4 + rnd.Intn(10)
+		g := randomAcyclicGraph(t, rnd, size, 2, 2, rnd.Intn(size*2))
+		sources := []int{0, 1}
+		sinks := []int{size - 2, size - 1}
+
+		var fromPaths []Vertix
+		for _, path := range g.PathsBetween(sources, sinks) {
+			fromPaths = append(fromPaths, path...)
+		}
+
+		require.Equal(t, vertixSet(fromPaths), vertixSet(g.UsefulVertices(sources, sinks)))
+	}
+}
+
+func TestUsefulVerticesWithLoop(t *testing.T) {
+	// 0 is a sensor, 3 is an action, and 1 and 2 signal each other
+	g := NewGraph(5)
+	for i := 0; i < 5; i++ {
+		addNode(t, g, i, fmt.Sprintf("node %d", i))
+	}
+	addV(t, g, 0, 1)
+	addV(t, g, 1, 2)
+	addV(t, g, 2, 1)
+	addV(t, g, 1, 3)
+	addV(t, g, 4, 4)
+	addV(t, g, 4, 3)
+
+	var fromPaths []Vertix
+	for _, path := range g.PathsBetween([]int{0}, []int{3}) {
+		fromPaths = append(fromPaths, path...)
+	}
+	assert.Equal(t, map[[2]int]bool{{0, 1}: true, {1, 3}: true}, vertixSet(fromPaths))
+
+	// the loop between 1 and 2 is kept, but 4 has no input and is left out
+	assert.Equal(t, map[[2]int]bool{{0, 1}: true, {1, 2}: true, {2, 1}: true, {1, 3}: true},
+		vertixSet(g.UsefulVertices([]int{0}, []int{3})))
+}
+
+func BenchmarkUsefulVertices(b *testing.B) {
+	g := randomAcyclicGraph(b, rand.New(rand.NewSource(42)), 60, 10, 10, 1000)
+	sources := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	sinks := []int{50, 51, 52, 53, 54, 55, 56, 57, 58, 59}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.UsefulVertices(sources, sinks)
+	}
+}
@@ -0,0 +1,269 @@
+// Package brain holds the neural nets the individuals think with, and the sensors and actions they connect
+package brain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+type (
+	// An individual's "brain" is a neural net specified by a set
+	// of Genes where each Gene specifies one connection in the neural net (see
+	// Genome comments above). Each neuron has a single output which is
+	// connected to a set of sinks where each sink is either an action output
+	// or another neuron. Each neuron has a set of input sources where each
+	// source is either a sensor or another neuron. There is no concept of
+	// layers in the net: it's a free-for-all topology with forward, backwards,
+	// and sideways connection allowed. Weighted connections are allowed
+	// directly from any source to any action.
+
+	// Currently, the genome does not specify the activation function used in
+	// the neurons.
+
+	// When the input is a sensor, the input value to the sink is the raw
+	// sensor value of type float and depends on the sensor. If the output
+	// is an action, the source's output value is interpreted by the action
+	// node and whether the action occurs or not depends on the action's
+	// implementation.
+
+	// In the genome, neurons are identified by 15-bit unsigned indices,
+	// which are reinterpreted as values in the range 0..p.genomeMaxLength-1
+	// by taking the 15-bit index modulo the max number of allowed neurons.
+	// In the neural net, the neurons that end up connected get new indices
+	// assigned sequentially starting at 0.
+
+	// NeuralNet encodes an individuals brain
+	NeuralNet struct {
+		// Contains the sensors that feed to something interesting
+		Sensors []Sensor
+
+		Neurons []*Neuron
+
+		Connections []Connection
+
+		// the number of genes in the genome this net was built from that ended up as a connection
+		ExpressedGenes int
+	}
+
+	Connection struct {
+		From Source // Either a sensor, or a neuron
+		To   Sink   // either an action, or a neuron
+
+		// multiplier is a value between -1.0..1.0,
+		multiplier float64
+	}
+
+	Neuron struct {
+		id int
+		// when value reaches 1.0, the neuron will fire
+		// until then it will accumulate into the value,
+		// a state which survives between steps
+		Value float64
+	}
+
+	Source interface {
+		Get()
+		name() string
+	}
+	Sink interface {
+		Set()
+		name() string
+	}
+
+	ActionSink struct {
+		action Action
+	}
+
+	SensorInput struct {
+		s   Sensor
+		idx int
+	}
+)
+
+func (s SensorInput) Get() {}
+func (n *Neuron) Get()     {}
+func (n *Neuron) Set()     {}
+func (n ActionSink) Set()  {}
+
+func NewNeuralNet(noOfNeurons int) *NeuralNet {
+	return &NeuralNet{
+		Neurons: make([]*Neuron, noOfNeurons),
+	}
+}
+
+// Connect adds a connection with the given weight to the net. from is a Sensor or the id of a neuron,
+// and to is an Action or the id of a neuron
+func (n *NeuralNet) Connect(from, to interface{}, weight float64) {
+	conn := Connection{multiplier: weight}
+	switch src := from.(type) {
+	case Sensor:
+		conn.From = SensorInput{s: src, idx: n.getSensorOffset(src)}
+	case int:
+		conn.From = n.getNeuronByID(src)
+	}
+	switch dst := to.(type) {
+	case Action:
+		conn.To = ActionSink{action: dst}
+	case int:
+		conn.To = n.getNeuronByID(dst)
+	}
+	n.Connections = append(n.Connections, conn)
+}
+
+func (n *NeuralNet) getSensorOffset(s Sensor) int {
+	for idx, sensor := range n.Sensors {
+		if s == sensor {
+			return idx
+		}
+	}
+
+	n.Sensors = append(n.Sensors, s)
+	return len(n.Sensors) - 1
+}
+
+func (n *NeuralNet) getNeuronByID(id int) *Neuron {
+	neuron := n.Neurons[id]
+	if neuron == nil {
+		neuron = &Neuron{id: id}
+		n.Neurons[id] = neuron
+	}
+	return neuron
+}
+
+// Clone returns a copy of the net with neurons of its own, so that firing one net leaves the other untouched
+func (n *NeuralNet) Clone() *NeuralNet {
+	result := NewNeuralNet(len(n.Neurons))
+	result.ExpressedGenes = n.ExpressedGenes
+	result.Sensors = append([]Sensor(nil), n.Sensors...)
+	for id, neuron := range n.Neurons {
+		if neuron != nil {
+			result.Neurons[id] = &Neuron{id: neuron.id, Value: neuron.Value}
+		}
+	}
+
+	result.Connections = make([]Connection, 0, len(n.Connections))
+	for _, conn := range n.Connections {
+		if from, ok := conn.From.(*Neuron); ok {
+			conn.From = result.Neurons[from.id]
+		}
+		if to, ok := conn.To.(*Neuron); ok {
+			conn.To = result.Neurons[to.id]
+		}
+		result.Connections = append(result.Connections, conn)
+	}
+	return result
+}
+
+// Think sends the sensor inputs through the net, and returns how much every action is taken, between -1 and 1.
+// With RecurrenceDelayed, pending are the neurons that fired last step, and the neurons that fired this step are
+// returned to be passed in next step. exhausted tells if the signal budget ran out
+func (n *NeuralNet) Think(inputs []float64, pending []*Neuron, leak float64, budget int, recurrence Recurrence) (actions Actions, fired []*Neuron, exhausted bool) {
+	actions = make(Actions, NUM_ACTIONS)
+	var neuronFirings []*Neuron
+
+	if leak > 0 {
+		for _, neuron := range n.Neurons {
+			if neuron != nil {
+				neuron.Value *= 1 - leak
+			}
+		}
+	}
+
+	// this is the function that will be called whenever there is a signal.
+	// The recipient of the signal can be a neuron, or it can be an action sink
+	handleFiring := func(to Sink, v float64) {
+		switch dst := to.(type) {
+		case ActionSink:
+			actions[dst.action] += v
+		case *Neuron:
+			dst.Value += v
+			for dst.Value > 1 {
+				// a neuron will keep firing until it gets it's internal state under 1
+				neuronFirings = append(neuronFirings, dst)
+				dst.Value -= 1
+			}
+		}
+	}
+
+	// fire is called for every neuron firing, and sends the signal on to everything the neuron is connected to
+	fire := func(current *Neuron) {
+		for _, conn := range n.Connections {
+			if conn.From != current {
+				continue
+			}
+			handleFiring(conn.To, conn.multiplier*1)
+		}
+	}
+
+	delayed := recurrence == RecurrenceDelayed
+	if delayed {
+		// With delayed recurrence, the neurons that fired during the last step deliver their signals now,
+		// and whatever they trigger in turn waits until the next step
+		if len(pending) > budget {
+			exhausted = true
+			pending = pending[:budget]
+		}
+		for _, current := range pending {
+			fire(current)
+		}
+	}
+
+	// Next step is to fire the connections to the sensor inputs
+	for _, conn := range n.Connections {
+		sensor, ok := conn.From.(SensorInput)
+		if !ok {
+			continue
+		}
+		srcValue := inputs[sensor.idx]
+		handleFiring(conn.To, conn.multiplier*srcValue)
+	}
+
+	if delayed {
+		fired = neuronFirings
+	} else {
+		// If neurons received signals in the last step, we could now have new signals that we need to handle
+		// Since the neural net is not an acyclic graph, we limit the number of signals we allow per step and individual
+		// We could deal with this in other ways, this method was chosen mostly because it is simple
+		iterLeft := budget
+		for len(neuronFirings) > 0 && iterLeft > 0 {
+			iterLeft--
+			current := neuronFirings[0]
+			neuronFirings = neuronFirings[1:]
+			fire(current)
+		}
+		if len(neuronFirings) > 0 {
+			exhausted = true
+		}
+	}
+
+	for idx, action := range actions {
+		actions[idx] = math.Tanh(action)
+	}
+	return actions, fired, exhausted
+}
+
+// Reset sets all neurons back to their resting state
+func (n *NeuralNet) Reset() {
+	for _, neuron := range n.Neurons {
+		if neuron != nil {
+			neuron.Value = 0
+		}
+	}
+}
+
+func (n *NeuralNet) String() string {
+	if len(n.Connections) == 0 {
+		return "{}"
+	}
+
+	var sensors []string
+	for idx, conn := range n.Connections {
+		sensors = append(sensors, fmt.Sprintf("%d:%s", idx, conn))
+	}
+	return strings.Join(sensors, "\n")
+}
+
+func (conn Connection) String() string {
+	return fmt.Sprintf("%s -[%03f]-> %s", conn.From.name(), conn.multiplier, conn.To.name())
+}
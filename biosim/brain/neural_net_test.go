@@ -0,0 +1,43 @@
+package brain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loopingBrain returns a brain with a neuron that excites itself, so it keeps firing once the BLOCK sensor starts it
+func loopingBrain() *NeuralNet {
+	net := NewNeuralNet(1)
+	net.Connect(BLOCK, 0, 1.5)
+	net.Connect(0, 0, 2)
+	net.Connect(0, MOVE_X, 0.5)
+	return net
+}
+
+func TestNeuralNet_String(t *testing.T) {
+	assert.Equal(t, "0:BLOCK -[1.500000]-> N0\n1:N0 -[2.000000]-> N0\n2:N0 -[0.500000]-> MOVE_X", loopingBrain().String())
+	assert.Equal(t, "{}", NewNeuralNet(1).String())
+}
+
+func TestNeuralNet_cloneDoesNotShareNeurons(t *testing.T) {
+	net := loopingBrain()
+	net.Neurons[0].Value = 0.7
+
+	clone := net.Clone()
+	require.Len(t, clone.Connections, len(net.Connections))
+	require.Equal(t, net.String(), clone.String())
+	require.Equal(t, 0.7, clone.Neurons[0].Value)
+
+	clone.Neurons[0].Value = 0.2
+	require.Equal(t, 0.7, net.Neurons[0].Value)
+	for _, conn := range clone.Connections {
+		if neuron, ok := conn.From.(*Neuron); ok {
+			require.Same(t, clone.Neurons[0], neuron)
+		}
+		if neuron, ok := conn.To.(*Neuron); ok {
+			require.Same(t, clone.Neurons[0], neuron)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package brain
+
+import "fmt"
+
+// Recurrence describes how neuron firings are propagated through the brain
+type Recurrence uint8
+
+const (
+	// RecurrenceBudget propagates firings within the same step, until no neuron fires any more or the
+	// signal budget is spent
+	RecurrenceBudget Recurrence = iota
+
+	// RecurrenceDelayed delivers the signals of a neuron that fires during a step at the start of the next
+	// simulation step, like biosim4 does
+	RecurrenceDelayed
+)
+
+var recurrenceNames = map[Recurrence]string{
+	RecurrenceBudget:  "budget",
+	RecurrenceDelayed: "delayed",
+}
+
+func (r Recurrence) String() string {
+	return recurrenceNames[r]
+}
+
+// Set implements flag.Value
+func (r *Recurrence) Set(s string) error {
+	for recurrence, name := range recurrenceNames {
+		if name == s {
+			*r = recurrence
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown recurrence %q", s)
+}
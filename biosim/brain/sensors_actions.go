@@ -1,8 +1,12 @@
-package main
+package brain
 
 type (
 	Sensor uint8
 	Action uint8
+
+	// Actions encodes the actions taken by an individual. The offset corresponds to the Action value,
+	// and the float value at the index says how much an action is taken
+	Actions = []float64
 )
 
 // Place the sensor neuron you want enabled prior to NUM_SENSES. Any
@@ -19,6 +23,7 @@ const (
 	BOUNDARY_DIST_Y               // I Y distance to the nearest edge of world
 	AGE                           // I
 	BLOCK                         // I 1 if the individual was blocked last step, 0 otherwise
+	SPECIES_FWD                   // W 1 if the individual ahead is of the same species, -1 if it is another species
 	NUM_SENSES                    // <<------------------ END OF ACTIVE SENSES MARKER
 )
 
@@ -56,4 +61,5 @@ var sensorNames = map[Sensor]string{
 	BOUNDARY_DIST_Y: "BOUNDARY_DIST_Y",
 	AGE:             "AGE",
 	BLOCK:           "BLOCK",
+	SPECIES_FWD:     "SPECIES_FWD",
 }
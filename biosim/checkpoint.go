@@ -0,0 +1,42 @@
+package biosim
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+type (
+	// checkpoint is a dump of the genomes of a population. It can't be resumed from, but it can be the gene
+	// pool of the pool immigration policy
+	checkpoint struct {
+		Generation int             `json:"generation"`
+		Genomes    []genome.Genome `json:"genomes"`
+	}
+)
+
+func writeCheckpoint(filename string, generation int, peeps []*world.Individual) error {
+	cp := checkpoint{Generation: generation}
+	for _, peep := range peeps {
+		cp.Genomes = append(cp.Genomes, peep.Genome)
+	}
+	data, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, os.ModePerm)
+}
+
+func readCheckpoint(filename string) (*checkpoint, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	cp := &checkpoint{}
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
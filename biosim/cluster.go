@@ -0,0 +1,180 @@
+package biosim
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+type (
+	// Reproduction decides how the survivors of a species share the offspring of the next generation
+	Reproduction uint8
+
+	// cluster is a group of similar genomes within a species, like a species in NEAT.
+	// Genomes belong to the first cluster whose representative is compatible enough with them
+	cluster struct {
+		id      int
+		species int
+		// representative holds the connections of the genome that stands for the cluster
+		representative genome.Connections
+		size           int // the number of individuals in the cluster at the end of the last generation
+	}
+)
+
+const (
+	// ReproduceCopy gives every survivor the same number of offspring
+	ReproduceCopy Reproduction = iota
+
+	// ReproduceShared clusters the genomes, and gives every cluster a share of the offspring that depends
+	// on how large a part of it survived, instead of on how many survivors it has. That way a solution
+	// that many individuals found doesn't crowd out the rest. Every cluster with survivors is guaranteed
+	// a few offspring, so new ideas get a few generations to prove themselves
+	ReproduceShared
+)
+
+const (
+	COMPATIBILITY_THRESHOLD = 1.0 // genomes further apart than this end up in different clusters
+	MIN_CLUSTER_OFFSPRING   = 2
+)
+
+var reproductionNames = map[Reproduction]string{
+	ReproduceCopy:   "copy",
+	ReproduceShared: "shared",
+}
+
+func (r Reproduction) String() string {
+	return reproductionNames[r]
+}
+
+// Set implements flag.Value
+func (r *Reproduction) Set(s string) error {
+	for reproduction, name := range reproductionNames {
+		if name == s {
+			*r = reproduction
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown reproduction %q", s)
+}
+
+// clusterPopulation puts every individual in the world in a cluster. Clusters from the last generation are
+// kept as long as they have members, and get a new representative picked among them
+func (s *island) clusterPopulation() {
+	for _, c := range s.clusters {
+		c.size = 0
+	}
+	for _, peep := range s.world.Peeps {
+		connections := peep.Genome.Connections()
+		var home *cluster
+		for _, c := range s.clusters {
+			if c.species == peep.Species && c.representative.Distance(connections) < COMPATIBILITY_THRESHOLD {
+				home = c
+				break
+			}
+		}
+		if home == nil {
+			home = &cluster{
+				id:             s.world.IDs.NextCluster(),
+				species:        peep.Species,
+				representative: connections,
+			}
+			s.clusters = append(s.clusters, home)
+		}
+		home.size++
+		peep.Cluster = home.id
+	}
+
+	members := map[int][]*world.Individual{}
+	for _, peep := range s.world.Peeps {
+		members[peep.Cluster] = append(members[peep.Cluster], peep)
+	}
+	alive := s.clusters[:0]
+	for _, c := range s.clusters {
+		if c.size > 0 {
+			c.representative = members[c.id][s.rnd.Intn(c.size)].Genome.Connections()
+			alive = append(alive, c)
+		}
+	}
+	s.clusters = alive
+}
+
+// clusterSizes returns the sizes of the clusters, largest first
+func (s *island) clusterSizes() []int {
+	sizes := make([]int, 0, len(s.clusters))
+	for _, c := range s.clusters {
+		sizes = append(sizes, c.size)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	return sizes
+}
+
+// sharedOffspring picks the parents of the offspring of a species, with fitness sharing between the clusters
+// the survivors belong to. It returns one parent for every child
+func (s *island) sharedOffspring(survivors []*world.Individual, offspring int) []*world.Individual {
+	byCluster := map[int][]*world.Individual{}
+	var ids []int
+	for _, peep := range survivors {
+		if _, ok := byCluster[peep.Cluster]; !ok {
+			ids = append(ids, peep.Cluster)
+		}
+		byCluster[peep.Cluster] = append(byCluster[peep.Cluster], peep)
+	}
+	sort.Ints(ids)
+
+	// the fitness of a cluster is the share of its members that survived
+	sizes := map[int]int{}
+	for _, c := range s.clusters {
+		sizes[c.id] = c.size
+	}
+	fitness := make([]float64, len(ids))
+	total := 0.0
+	for idx, id := range ids {
+		size := sizes[id]
+		if size < len(byCluster[id]) {
+			// migrants can come from clusters this island has never seen
+			size = len(byCluster[id])
+		}
+		fitness[idx] = float64(len(byCluster[id])) / float64(size)
+		total += fitness[idx]
+	}
+
+	protected := MIN_CLUSTER_OFFSPRING
+	if protected*len(ids) > offspring {
+		protected = offspring / len(ids)
+	}
+	quotas := make([]int, len(ids))
+	left := offspring
+	for idx := range ids {
+		quotas[idx] = protected
+		left -= protected
+	}
+	share := left
+	for idx := range ids {
+		n := int(float64(share) * fitness[idx] / total)
+		quotas[idx] += n
+		left -= n
+	}
+	// what's left after rounding down goes to the fittest clusters
+	order := make([]int, len(ids))
+	for idx := range order {
+		order[idx] = idx
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return fitness[order[i]] > fitness[order[j]]
+	})
+	for i := 0; left > 0; i = (i + 1) % len(order) {
+		quotas[order[i]]++
+		left--
+	}
+
+	parents := make([]*world.Individual, 0, offspring)
+	for idx, id := range ids {
+		members := byCluster[id]
+		for i := 0; i < quotas[idx]; i++ {
+			parents = append(parents, members[i%len(members)])
+		}
+	}
+	return parents
+}
@@ -0,0 +1,63 @@
+package biosim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+func TestClusterPopulation(t *testing.T) {
+	w := &world.World{XSize: 10, YSize: 10, Cells: make([]world.Cell, 100), Config: world.DefaultConfig()}
+	s := newIsland(w, DefaultConfig(), 1)
+	one, other := genome.MakeRandomGenome(10, rnd), genome.MakeRandomGenome(10, rnd)
+	for i := 0; i < 3; i++ {
+		w.Peeps = append(w.Peeps, &world.Individual{Genome: one}, &world.Individual{Genome: other})
+	}
+	w.Peeps = append(w.Peeps, &world.Individual{Genome: one, Species: 1})
+
+	s.clusterPopulation()
+	require.Len(t, s.clusters, 3)
+	assert.Equal(t, []int{3, 3, 1}, s.clusterSizes())
+	assert.Equal(t, w.Peeps[0].Cluster, w.Peeps[2].Cluster)
+	assert.NotEqual(t, w.Peeps[0].Cluster, w.Peeps[1].Cluster)
+	assert.NotEqual(t, w.Peeps[0].Cluster, w.Peeps[6].Cluster, "clusters don't cross species")
+
+	// clusters live on from one generation to the next
+	ids := []int{w.Peeps[0].Cluster, w.Peeps[1].Cluster}
+	w.Peeps = w.Peeps[:2]
+	s.clusterPopulation()
+	require.Len(t, s.clusters, 2)
+	assert.Equal(t, ids, []int{w.Peeps[0].Cluster, w.Peeps[1].Cluster})
+}
+
+func TestSharedOffspring(t *testing.T) {
+	s := newIsland(&world.World{}, DefaultConfig(), 1)
+	s.clusters = []*cluster{{id: 1, size: 90}, {id: 2, size: 10}}
+	var survivors []*world.Individual
+	for i := 0; i < 9; i++ {
+		survivors = append(survivors, &world.Individual{Cluster: 1})
+	}
+	for i := 0; i < 5; i++ {
+		survivors = append(survivors, &world.Individual{Cluster: 2})
+	}
+
+	parents := s.sharedOffspring(survivors, 100)
+	require.Len(t, parents, 100)
+	counts := map[int]int{}
+	for _, parent := range parents {
+		counts[parent.Cluster]++
+	}
+	// one in ten survived in the big cluster, and one in two in the small one
+	assert.Equal(t, map[int]int{1: 18, 2: 82}, counts)
+
+	// every cluster gets its protected offspring, even when there are few to go around
+	parents = s.sharedOffspring(survivors, 4)
+	counts = map[int]int{}
+	for _, parent := range parents {
+		counts[parent.Cluster]++
+	}
+	assert.Equal(t, map[int]int{1: 2, 2: 2}, counts)
+}
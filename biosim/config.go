@@ -0,0 +1,126 @@
+package biosim
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/render"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+type (
+	// Config holds the settings of a simulation
+	Config struct {
+		// Config holds the settings of the worlds of the islands, and the individuals living in them
+		world.Config
+
+		// ResetEachGeneration clears the neuron state of all individuals before a new generation starts
+		ResetEachGeneration bool
+
+		// Coloring decides how individuals are colored in the frames of the dashboard
+		Coloring render.Coloring
+
+		// Workers is the number of goroutines the individuals think and move on
+		Workers int
+
+		// CheckpointDir is the directory checkpoints are written to
+		CheckpointDir string
+
+		// LineageLog is the file every birth and cull is logged to. Empty means no log
+		LineageLog string
+
+		// MutationSchedule decides whether the global mutation rate adapts to how the population is doing
+		MutationSchedule MutationSchedule
+
+		// Immigration decides where the immigrants in every new generation come from
+		Immigration ImmigrationPolicy
+
+		// ImmigrationRate is the share of every new generation that are immigrants, instead of offspring of
+		// the survivors
+		ImmigrationRate float64
+
+		// GenePool is the checkpoint file immigrants get their genomes from with the pool immigration policy
+		GenePool string
+
+		// Reproduction decides how the survivors of a species share the offspring of the next generation
+		Reproduction Reproduction
+
+		// Scenarios describe the worlds and how they change. With several islands, they take turns using them
+		Scenarios world.Scenarios
+
+		// Islands is the number of worlds that run side by side, each with a population of its own
+		Islands int
+
+		// Migrants is the number of survivors that leave every island when it is time to migrate
+		Migrants int
+
+		// MigrateEvery lets migrants move every n:th generation. 0 keeps the islands apart
+		MigrateEvery int
+
+		// Topology decides which islands migrants can go to
+		Topology Topology
+
+		// MutationRate is the chance of a mutation the run starts with, x in 1000
+		MutationRate int64
+
+		// Seed seeds the random numbers of the simulation. Simulations with the same config and seed make the
+		// same run. 0 picks a seed from the clock
+		Seed int64
+	}
+)
+
+func DefaultConfig() Config {
+	return Config{
+		Config:          world.DefaultConfig(),
+		Coloring:        render.ColorGenome,
+		Workers:         runtime.NumCPU(),
+		CheckpointDir:   ".",
+		Scenarios:       world.Scenarios{world.DefaultScenario()},
+		Islands:         1,
+		Migrants:        MIGRANTS,
+		MigrateEvery:    MIGRATE_EVERY,
+		Immigration:     ImmigrationRandom,
+		ImmigrationRate: IMMIGRATION_RATE,
+		MutationRate:    genome.MUTATION_RATE,
+	}
+}
+
+// validateMutationRate checks that a simulation can run with the mutation rate, x in 1000
+func validateMutationRate(rate int64) error {
+	if rate < MIN_MUTATION_RATE || rate > MAX_MUTATION_RATE {
+		return fmt.Errorf("the mutation rate must be between %d and %d", MIN_MUTATION_RATE, MAX_MUTATION_RATE)
+	}
+	return nil
+}
+
+// validate checks that a simulation can be run with the config
+func (c Config) validate() error {
+	if err := validateMutationRate(c.MutationRate); err != nil {
+		return err
+	}
+	if err := c.GenomeBounds.Validate(); err != nil {
+		return err
+	}
+	if c.ImmigrationRate < 0 || c.ImmigrationRate > 1 {
+		return fmt.Errorf("the immigration rate must be between 0 and 1")
+	}
+	if c.Islands < 1 {
+		return fmt.Errorf("there must be at least 1 island")
+	}
+	if len(c.Scenarios) == 0 {
+		return fmt.Errorf("there must be at least 1 scenario")
+	}
+	if c.Species.Population() > world.MAX_PEEPS {
+		return fmt.Errorf("a world holds at most %d individuals, build with -tags cells32 for more", world.MAX_PEEPS)
+	}
+	for _, scenario := range c.Scenarios {
+		if err := scenario.Validate(); err != nil {
+			return err
+		}
+		if scenario.Smallest() <= c.Species.Population() {
+			return fmt.Errorf("a world with %d free cells has no room for a population of %d", scenario.Smallest(), c.Species.Population())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package genome
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// the self-adaptive mutation of the mutation rate of a genome. See Genome.MutationBias
+type biasMutation struct{}
+
+const (
+	MAX_BIAS   = 5   // mutation biases are kept within plus minus this
+	BIAS_SIGMA = 0.2 // standard deviation of the change to a mutation bias
+)
+
+// biasMutation nudges the mutation bias of the genome. With this operator turned on, genomes find their own
+// mutation rate: lineages whose rate suits them survive, and pass their rate on
+func (biasMutation) Name() string  { return "bias" }
+func (biasMutation) PerGene() bool { return false }
+func (biasMutation) Mutate(g *Genome, _ int, rnd *rand.Rand) string {
+	change := rnd.NormFloat64() * BIAS_SIGMA
+	g.MutationBias = math.Max(-MAX_BIAS, math.Min(MAX_BIAS, g.MutationBias+change))
+	return fmt.Sprintf("%+.2f", change)
+}
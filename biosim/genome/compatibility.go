@@ -0,0 +1,61 @@
+package genome
+
+import "math"
+
+const (
+	DISJOINT_COEFFICIENT = 1.0 // how much connections only one of the genomes has add to the distance
+	WEIGHT_COEFFICIENT   = 0.5 // how much weight differences of shared connections add to the distance
+)
+
+// Connections maps the connections a genome makes to their weights. Comparing genomes by their connections
+// saves working them out again for every comparison
+type Connections map[Gene]float64
+
+// Connections returns the connections the genome makes. Like BuildNet, it only looks at the first gene
+// making a connection
+func (g Genome) Connections() Connections {
+	result := Connections{}
+	for _, gene := range g.Genes {
+		key := gene.normalize(g.NoOfNeurons)
+		key.Weight = 0
+		if _, ok := result[key]; !ok {
+			result[key] = float64(gene.weightAsFloat())
+		}
+	}
+	return result
+}
+
+// Compatibility is the distance between two genomes. Genomes that make the same connections with
+// the same weights have distance 0
+func Compatibility(a, b Genome) float64 {
+	return a.Connections().Distance(b.Connections())
+}
+
+// Distance is the Compatibility of the genomes the connections belong to
+func (ca Connections) Distance(cb Connections) float64 {
+	disjoint := 0
+	matching := 0
+	weightDiff := 0.0
+	for key, wa := range ca {
+		if wb, ok := cb[key]; ok {
+			matching++
+			weightDiff += math.Abs(wa - wb)
+		} else {
+			disjoint++
+		}
+	}
+	disjoint += len(cb) - matching
+
+	n := len(ca)
+	if len(cb) > n {
+		n = len(cb)
+	}
+	if n == 0 {
+		return 0
+	}
+	distance := DISJOINT_COEFFICIENT * float64(disjoint) / float64(n)
+	if matching > 0 {
+		distance += WEIGHT_COEFFICIENT * weightDiff / float64(matching)
+	}
+	return distance
+}
@@ -0,0 +1,28 @@
+package genome
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/brain"
+)
+
+func TestCompatibility(t *testing.T) {
+	genome := MakeRandomGenome(8, rnd)
+	assert.Equal(t, 0.0, Compatibility(genome, genome))
+
+	gene := Gene{SourceIsSensor: true, SourceID: uint8(brain.LOC_X), SinkIsAction: true, SinkID: uint8(brain.MOVE_X), Weight: 8192}
+	other := Gene{SourceIsSensor: true, SourceID: uint8(brain.AGE), SinkIsAction: true, SinkID: uint8(brain.MOVE_Y), Weight: 100}
+	assert.Equal(t, 0.0, Compatibility(Genome{Genes: []Gene{gene, other}}, Genome{Genes: []Gene{other, gene}}),
+		"the order of the genes doesn't matter")
+
+	a := Genome{Genes: []Gene{gene}}
+	gene.Weight = -8192
+	b := Genome{Genes: []Gene{gene}}
+	assert.InDelta(t, WEIGHT_COEFFICIENT*2, Compatibility(a, b), 0.0001)
+
+	gene.SinkID = uint8(brain.MOVE_Y)
+	c := Genome{Genes: []Gene{gene}}
+	assert.InDelta(t, DISJOINT_COEFFICIENT*2, Compatibility(a, c), 0.0001)
+	assert.Equal(t, Compatibility(a, c), a.Connections().Distance(c.Connections()))
+}
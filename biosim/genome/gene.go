@@ -0,0 +1,311 @@
+// Package genome holds the genomes brains are built from, and the ways they mutate
+package genome
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/systay/gobiosim/biosim/brain"
+)
+
+type (
+	// Gene specifies one synaptic connection in a neural net. Each
+	// connection has an input (source) which is either a sensor or another neuron.
+	// Each connection has an output, which is either an action or another neuron.
+	// Each connection has a floating point weight derived from a signed 16-bit
+	// value. The signed integer weight is scaled to a small range, then cubed
+	// to provide fine resolution near zero.
+	Gene struct {
+		SourceIsSensor bool // sensor if true, otherwise neuron
+		SourceID       uint8
+		SinkIsAction   bool // action if true, otherwise neuron
+		SinkID         uint8
+		Weight         int16
+	}
+
+	// Genome defines an individuals' genome. It consists of a set of genes.
+	// The genome is used to build an individuals neural net brain
+	// NoOfNeurons should not be a
+	Genome struct {
+		Genes       []Gene
+		NoOfNeurons int
+
+		// MutationBias scales the chance of every mutation of offspring of this genome by e^MutationBias,
+		// so 0 leaves the rates as they are. It is inherited, and changed by the bias mutation
+		MutationBias float64
+	}
+)
+
+// GenomeBounds limits how long genomes can get, and how many neurons they can have.
+// Random genomes are created within the bounds, and mutations that would break them are not made
+type GenomeBounds struct {
+	MinGenes, MaxGenes     int
+	MinNeurons, MaxNeurons int
+}
+
+const (
+	MIN_GENES     = 2
+	MAX_GENES     = 64
+	INITIAL_GENES = 21
+	MIN_NEURONS   = 1
+	MAX_NEURONS   = 32
+
+	// MAX_NEURON_IDS is the most neurons a genome can have, since genes address them with a uint8
+	MAX_NEURON_IDS = math.MaxUint8
+)
+
+func DefaultGenomeBounds() GenomeBounds {
+	return GenomeBounds{
+		MinGenes:   MIN_GENES,
+		MaxGenes:   MAX_GENES,
+		MinNeurons: MIN_NEURONS,
+		MaxNeurons: MAX_NEURONS,
+	}
+}
+
+func (b GenomeBounds) Validate() error {
+	if b.MinGenes < 1 || b.MaxGenes < b.MinGenes {
+		return fmt.Errorf("genome length must be at least 1, and max can't be below min: %d-%d", b.MinGenes, b.MaxGenes)
+	}
+	if b.MinNeurons < 0 || b.MaxNeurons < b.MinNeurons {
+		return fmt.Errorf("neuron count can't be negative, and max can't be below min: %d-%d", b.MinNeurons, b.MaxNeurons)
+	}
+	if b.MaxNeurons > MAX_NEURON_IDS {
+		return fmt.Errorf("a genome can have at most %d neurons, got a max of %d", MAX_NEURON_IDS, b.MaxNeurons)
+	}
+	return nil
+}
+
+// Allows tells if the genome is within the bounds
+func (b GenomeBounds) Allows(g Genome) bool {
+	return len(g.Genes) >= b.MinGenes && len(g.Genes) <= b.MaxGenes &&
+		g.NoOfNeurons >= b.MinNeurons && g.NoOfNeurons <= b.MaxNeurons
+}
+
+// RandomGenome makes a random genome within the bounds, with at most initialGenes genes, drawing from rnd
+func (b GenomeBounds) RandomGenome(initialGenes int, rnd *rand.Rand) Genome {
+	longest := initialGenes
+	if longest > b.MaxGenes {
+		longest = b.MaxGenes
+	}
+	size := b.MinGenes
+	if longest > size {
+		size += rnd.Intn(longest - size + 1)
+	}
+	genome := MakeRandomGenome(size, rnd)
+	if genome.NoOfNeurons < b.MinNeurons {
+		genome.NoOfNeurons = b.MinNeurons
+	}
+	if genome.NoOfNeurons > b.MaxNeurons {
+		genome.NoOfNeurons = b.MaxNeurons
+	}
+	return genome
+}
+
+func (g Gene) weightAsFloat() float32 {
+	return float32(g.Weight) / 8192.0
+}
+
+func randInt16(rnd *rand.Rand) int16 {
+	return int16(rnd.Int63() >> 48)
+}
+
+const NEURON_PREFERENCE = 4
+
+func makeRandomGene(rnd *rand.Rand) Gene {
+	gene := Gene{}
+	gene.SourceIsSensor = rnd.Int()%NEURON_PREFERENCE == 0
+	gene.SinkIsAction = rnd.Int()%NEURON_PREFERENCE == 0
+	gene.SourceID = randUint8(rnd)
+	gene.SinkID = randUint8(rnd)
+	gene.Weight = -randInt16(rnd) + randInt16(rnd)
+	return gene
+}
+
+func MakeRandomGenome(size int, rnd *rand.Rand) Genome {
+	genome := Genome{
+		Genes:       make([]Gene, 0, size),
+		NoOfNeurons: int(math.Sqrt(float64(size))),
+	}
+	for i := 0; i < size; i++ {
+		genome.Genes = append(genome.Genes, makeRandomGene(rnd).normalize(genome.NoOfNeurons))
+	}
+	return genome
+}
+
+func randUint8(rnd *rand.Rand) uint8 {
+	return uint8(rnd.Int31n(255))
+}
+
+type identifiable struct {
+	objects []interface{}
+}
+
+func (id *identifiable) idOf(obj interface{}) (int, bool) {
+	for idx, item := range id.objects {
+		if obj == item {
+			return idx, false
+		}
+	}
+	id.objects = append(id.objects, obj)
+	return len(id.objects) - 1, true
+}
+
+var TooSimple = fmt.Errorf("too simple brain")
+
+func (g Genome) BuildNet() (*brain.NeuralNet, error) {
+	graph, vertices, geneVertices, err := buildGraph(&g)
+	if err != nil {
+		return nil, err
+	}
+	if len(vertices) == 0 {
+		return nil, TooSimple
+	}
+	result := brain.NewNeuralNet(g.NoOfNeurons)
+
+	seen := map[int]interface{}{}
+	for _, vertix := range vertices {
+		seen[vertix.From*graph.Size()+vertix.To] = nil
+		result.Connect(graph.GetNode(vertix.From), graph.GetNode(vertix.To), vertix.Data.(float64))
+	}
+
+	for _, vIdx := range geneVertices {
+		if _, ok := seen[vIdx]; ok {
+			result.ExpressedGenes++
+		}
+	}
+
+	return result, nil
+}
+
+// buildGraph creates a graph from the genes, and finds the vertices in it that are on a path from a sensor to an action.
+// For every gene, it also returns the matrix offset of the vertix the gene created, or -1 if the gene
+// did not create a vertix of its own, because it has no weight or because an earlier gene already connects the same nodes
+func buildGraph(g *Genome) (*brain.Graph, []brain.Vertix, []int, error) {
+	maxPossibleSize := len(g.Genes) * 2
+	graph := brain.NewGraph(maxPossibleSize)
+	nodes := &identifiable{}
+	var sensors, actions []int
+	geneVertices := make([]int, 0, len(g.Genes))
+	for _, gene := range g.Genes {
+		var isSensor, isAction bool
+		var obj interface{}
+		if gene.SourceIsSensor {
+			obj = getSensor(gene.SourceID)
+			isSensor = true
+		} else {
+			if g.NoOfNeurons == 0 {
+				g.NoOfNeurons = 1
+			}
+			obj = int(gene.SourceID) % g.NoOfNeurons
+		}
+		srcID, added := nodes.idOf(obj)
+		if added {
+			err := graph.AddNode(srcID, obj)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if isSensor {
+				sensors = append(sensors, srcID)
+			}
+		}
+
+		if gene.SinkIsAction {
+			obj = getAction(gene.SinkID)
+			isAction = true
+		} else {
+			if g.NoOfNeurons == 0 {
+				g.NoOfNeurons = 1
+			}
+			obj = int(gene.SinkID) % g.NoOfNeurons
+		}
+		dstID, added := nodes.idOf(obj)
+		if added {
+			err := graph.AddNode(dstID, obj)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if isAction {
+				actions = append(actions, dstID)
+			}
+		}
+
+		if gene.Weight == 0 || graph.GetVertix(srcID, dstID) != nil {
+			// a connection without weight can't carry a signal, and only the first gene connecting two nodes is used
+			geneVertices = append(geneVertices, -1)
+			continue
+		}
+		weight := float64(gene.Weight) / float64(math.MaxInt16)
+		err := graph.AddVertix(srcID, dstID, weight)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		geneVertices = append(geneVertices, srcID*graph.Size()+dstID)
+	}
+
+	vertices := graph.UsefulVertices(sensors, actions)
+	return graph, vertices, geneVertices, nil
+}
+
+func (g Gene) normalize(neuronCount int) Gene {
+	if g.SourceIsSensor ||
+		neuronCount == 0 { // this condition because if the genome has no neurons, the alternative would panic
+		g.SourceID = g.SourceID % uint8(brain.NUM_SENSES)
+	} else {
+		g.SourceID = g.SourceID % uint8(neuronCount)
+	}
+	if g.SinkIsAction || neuronCount == 0 {
+		g.SinkID = g.SinkID % uint8(brain.NUM_ACTIONS)
+	} else {
+		g.SinkID = g.SinkID % uint8(neuronCount)
+	}
+
+	return g
+}
+
+func getSensor(source uint8) brain.Sensor {
+	return brain.Sensor(source % uint8(brain.NUM_SENSES))
+}
+
+func getAction(source uint8) brain.Action {
+	return brain.Action(source % uint8(brain.NUM_ACTIONS))
+}
+
+// Clone copies the genome, giving every mutation operator its chance to change the copy. mutationRate is the
+// chance of a mutation, x in 1000, and the rates of the operators are relative to it. The mutations that
+// happened are described in the returned slice, which is empty if the clone is identical to the original.
+// The dice for the mutations are rolled with rnd
+func (g Genome) Clone(mutationRate int64, rates MutationRates, bounds GenomeBounds, rnd *rand.Rand) (output Genome, mutations []string) {
+	output = g
+	// the genes are mutated in place, so the offspring needs a slice of its own
+	output.Genes = append([]Gene(nil), g.Genes...)
+
+	mutate := func(op MutationOperator, gene int) {
+		if description := op.Mutate(&output, gene, rnd); description != "" {
+			mutations = append(mutations, op.Name()+" "+description)
+		}
+	}
+	for _, op := range MutationOperators {
+		rate := rates[op.Name()] * math.Exp(g.MutationBias)
+		if !op.PerGene() {
+			if !strikes(rate, mutationRate, rnd) {
+				continue
+			}
+			// these can change the size of the genome, so they work on a copy that is only kept if it stays in bounds
+			candidate := output
+			candidate.Genes = append([]Gene(nil), output.Genes...)
+			if description := op.Mutate(&candidate, -1, rnd); description != "" && bounds.Allows(candidate) {
+				output = candidate
+				mutations = append(mutations, op.Name()+" "+description)
+			}
+			continue
+		}
+		for idx := range output.Genes {
+			if strikes(rate, mutationRate, rnd) {
+				mutate(op, idx)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,65 @@
+package genome
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"testing"
+
+	"github.com/systay/gobiosim/biosim/brain"
+)
+
+func TestSimpleGenome(t *testing.T) {
+	genome := Genome{
+		NoOfNeurons: 1,
+		Genes:       []Gene{
+			{
+				SourceIsSensor: true,
+				SourceID:       uint8(brain.LOC_X),
+				SinkIsAction:   true,
+				SinkID:         uint8(brain.MOVE_X),
+				Weight:         100,
+			},
+		},
+	}
+
+	net2, err := genome.BuildNet()
+	require.NoError(t, err)
+	fmt.Println(net2)
+}
+func TestGeneExpression(t *testing.T) {
+	genome := Genome{
+		NoOfNeurons: 2,
+		Genes: []Gene{
+			// expressed: sensor -> action
+			{SourceIsSensor: true, SourceID: uint8(brain.LOC_X), SinkIsAction: true, SinkID: uint8(brain.MOVE_X), Weight: 100},
+			// dormant: same connection as the gene above
+			{SourceIsSensor: true, SourceID: uint8(brain.LOC_X), SinkIsAction: true, SinkID: uint8(brain.MOVE_X), Weight: 200},
+			// dormant: no weight
+			{SourceIsSensor: true, SourceID: uint8(brain.LOC_Y), SinkIsAction: true, SinkID: uint8(brain.MOVE_Y), Weight: 0},
+			// dormant: the neuron never reaches an action
+			{SourceIsSensor: true, SourceID: uint8(brain.AGE), SinkIsAction: false, SinkID: 1, Weight: 100},
+			// expressed: sensor -> neuron -> action
+			{SourceIsSensor: true, SourceID: uint8(brain.BLOCK), SinkIsAction: false, SinkID: 0, Weight: 100},
+			{SourceIsSensor: false, SourceID: 0, SinkIsAction: true, SinkID: uint8(brain.MOVE_Y), Weight: 100},
+		},
+	}
+
+	net, err := genome.BuildNet()
+	require.NoError(t, err)
+	require.Len(t, net.Connections, 3)
+
+	require.Equal(t, 3, net.ExpressedGenes)
+	require.Equal(t, 3, len(genome.Genes)-net.ExpressedGenes)
+}
+
+func TestNeuralNet_String(t *testing.T) {
+	// a random genome is allowed to be too simple to make a brain, so we keep trying until we get one
+	it := MakeRandomGenome(10, rnd)
+	net, err := it.BuildNet()
+	for err == TooSimple {
+		it = MakeRandomGenome(10, rnd)
+		net, err = it.BuildNet()
+	}
+	require.NoError(t, err)
+	fmt.Println(net.String())
+}
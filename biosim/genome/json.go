@@ -0,0 +1,58 @@
+package genome
+
+import "encoding/json"
+
+type (
+	// jsonGenome is the shape a Genome has when saved as JSON
+	jsonGenome struct {
+		Neurons      int        `json:"neurons"`
+		Genes        []jsonGene `json:"genes"`
+		MutationBias float64    `json:"mutation_bias,omitempty"`
+	}
+
+	jsonGene struct {
+		SourceIsSensor bool  `json:"source_is_sensor"`
+		SourceID       uint8 `json:"source_id"`
+		SinkIsAction   bool  `json:"sink_is_action"`
+		SinkID         uint8 `json:"sink_id"`
+		Weight         int16 `json:"weight"`
+	}
+)
+
+func (g Genome) MarshalJSON() ([]byte, error) {
+	result := jsonGenome{
+		Neurons:      g.NoOfNeurons,
+		Genes:        make([]jsonGene, 0, len(g.Genes)),
+		MutationBias: g.MutationBias,
+	}
+	for _, gene := range g.Genes {
+		result.Genes = append(result.Genes, jsonGene{
+			SourceIsSensor: gene.SourceIsSensor,
+			SourceID:       gene.SourceID,
+			SinkIsAction:   gene.SinkIsAction,
+			SinkID:         gene.SinkID,
+			Weight:         gene.Weight,
+		})
+	}
+	return json.Marshal(result)
+}
+
+func (g *Genome) UnmarshalJSON(data []byte) error {
+	var input jsonGenome
+	if err := json.Unmarshal(data, &input); err != nil {
+		return err
+	}
+	g.NoOfNeurons = input.Neurons
+	g.MutationBias = input.MutationBias
+	g.Genes = make([]Gene, 0, len(input.Genes))
+	for _, gene := range input.Genes {
+		g.Genes = append(g.Genes, Gene{
+			SourceIsSensor: gene.SourceIsSensor,
+			SourceID:       gene.SourceID,
+			SinkIsAction:   gene.SinkIsAction,
+			SinkID:         gene.SinkID,
+			Weight:         gene.Weight,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package genome
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// MutationOperator is one way of changing a genome
+	MutationOperator interface {
+		// Name identifies the operator in the configuration, and starts the description of every mutation it makes
+		Name() string
+
+		// PerGene operators get a chance to mutate every gene of the genome. The others get one chance per genome
+		PerGene() bool
+
+		// Mutate changes the genome in place, and describes what it did. gene is the index of the gene to mutate
+		// for PerGene operators, and -1 for the others. An empty description means nothing could be done.
+		// The random numbers come from rnd
+		Mutate(g *Genome, gene int, rnd *rand.Rand) string
+	}
+
+	// MutationRates holds the rate of every mutation operator, by name. A rate is relative to the global mutation
+	// rate, so with a rate of 0.5 the operator strikes half as often as the global rate says
+	MutationRates map[string]float64
+
+	// the mutation operators, each implementing MutationOperator
+	sourceMutation    struct{}
+	sinkMutation      struct{}
+	weightMutation    struct{}
+	gaussianMutation  struct{}
+	flipMutation      struct{}
+	replaceMutation   struct{}
+	insertMutation    struct{}
+	deleteMutation    struct{}
+	neuronMutation    struct{}
+	duplicateMutation struct{}
+	swapMutation      struct{}
+	invertMutation    struct{}
+)
+
+// MutationOperators are all known mutation operators, in the order they are applied
+var MutationOperators = []MutationOperator{
+	sourceMutation{},
+	sinkMutation{},
+	weightMutation{},
+	gaussianMutation{},
+	flipMutation{},
+	replaceMutation{},
+	insertMutation{},
+	deleteMutation{},
+	neuronMutation{},
+	duplicateMutation{},
+	swapMutation{},
+	invertMutation{},
+	biasMutation{},
+}
+
+const (
+	MUTATION_RATE  = 100  // the chance of a mutation, x in 1000
+	GAUSSIAN_SIGMA = 2000 // the standard deviation of the gaussian weight mutation
+)
+
+// DefaultMutationRates are the rates mutations have always had: every gene has one chance of getting its source,
+// sink or weight nudged, and every genome has one chance each of gaining a gene, losing a gene and changing its
+// number of neurons. The other operators are turned off
+func DefaultMutationRates() MutationRates {
+	return MutationRates{
+		"source":  1.0 / 3,
+		"sink":    1.0 / 3,
+		"weight":  1.0 / 3,
+		"insert":  1,
+		"delete":  1,
+		"neurons": 1,
+	}
+}
+
+func (r MutationRates) String() string {
+	var names []string
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var parts []string
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%g", name, r[name]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value. It takes a comma separated list of name=rate pairs,
+// and changes the rates of the named operators
+func (r MutationRates) Set(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("expected name=rate, got %q", part)
+		}
+		if !isMutationOperator(kv[0]) {
+			return fmt.Errorf("unknown mutation operator %q", kv[0])
+		}
+		rate, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil {
+			return err
+		}
+		r[kv[0]] = rate
+	}
+	return nil
+}
+
+func isMutationOperator(name string) bool {
+	for _, op := range MutationOperators {
+		if op.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+func MutationOperatorNames() string {
+	var names []string
+	for _, op := range MutationOperators {
+		names = append(names, op.Name())
+	}
+	return strings.Join(names, ", ")
+}
+
+// strikes rolls the dice for a mutation with the given rate, relative to the mutation rate
+func strikes(rate float64, mutationRate int64, rnd *rand.Rand) bool {
+	return rate > 0 && rnd.Float64()*1000 < rate*float64(mutationRate)
+}
+
+func (sourceMutation) Name() string  { return "source" }
+func (sourceMutation) PerGene() bool { return true }
+func (sourceMutation) Mutate(g *Genome, idx int, rnd *rand.Rand) string {
+	gene := g.Genes[idx]
+	gene.SourceID = uint8(int(gene.SourceID) + plusMinusOne(rnd))
+	g.Genes[idx] = gene.normalize(g.NoOfNeurons)
+	return strconv.Itoa(idx)
+}
+
+func (sinkMutation) Name() string  { return "sink" }
+func (sinkMutation) PerGene() bool { return true }
+func (sinkMutation) Mutate(g *Genome, idx int, rnd *rand.Rand) string {
+	gene := g.Genes[idx]
+	gene.SinkID = uint8(int(gene.SinkID) + plusMinusOne(rnd))
+	g.Genes[idx] = gene.normalize(g.NoOfNeurons)
+	return strconv.Itoa(idx)
+}
+
+func (weightMutation) Name() string  { return "weight" }
+func (weightMutation) PerGene() bool { return true }
+func (weightMutation) Mutate(g *Genome, idx int, rnd *rand.Rand) string {
+	gene := g.Genes[idx]
+	gene.Weight = int16(int(gene.Weight) + plusMinusOne(rnd)*1000)
+	g.Genes[idx] = gene.normalize(g.NoOfNeurons)
+	return strconv.Itoa(idx)
+}
+
+// gaussianMutation perturbs the weight by a normally distributed amount, so small changes are common and big rare
+func (gaussianMutation) Name() string  { return "gaussian" }
+func (gaussianMutation) PerGene() bool { return true }
+func (gaussianMutation) Mutate(g *Genome, idx int, rnd *rand.Rand) string {
+	weight := float64(g.Genes[idx].Weight) + rnd.NormFloat64()*GAUSSIAN_SIGMA
+	g.Genes[idx].Weight = int16(math.Max(math.MinInt16, math.Min(math.MaxInt16, weight)))
+	return strconv.Itoa(idx)
+}
+
+// flipMutation turns a sensor source into a neuron or back, or does the same to an action sink
+func (flipMutation) Name() string  { return "flip" }
+func (flipMutation) PerGene() bool { return true }
+func (flipMutation) Mutate(g *Genome, idx int, rnd *rand.Rand) string {
+	gene := g.Genes[idx]
+	side := "source"
+	if rnd.Intn(2) == 0 {
+		gene.SourceIsSensor = !gene.SourceIsSensor
+	} else {
+		gene.SinkIsAction = !gene.SinkIsAction
+		side = "sink"
+	}
+	g.Genes[idx] = gene.normalize(g.NoOfNeurons)
+	return fmt.Sprintf("%d %s", idx, side)
+}
+
+func (replaceMutation) Name() string  { return "replace" }
+func (replaceMutation) PerGene() bool { return true }
+func (replaceMutation) Mutate(g *Genome, idx int, rnd *rand.Rand) string {
+	g.Genes[idx] = makeRandomGene(rnd).normalize(g.NoOfNeurons)
+	return strconv.Itoa(idx)
+}
+
+func (insertMutation) Name() string  { return "insert" }
+func (insertMutation) PerGene() bool { return false }
+func (insertMutation) Mutate(g *Genome, _ int, rnd *rand.Rand) string {
+	if len(g.Genes) == 0 {
+		g.Genes = append(g.Genes, makeRandomGene(rnd))
+		return "0"
+	}
+	pos := rnd.Intn(len(g.Genes))
+	g.Genes = append(g.Genes[:pos+1], g.Genes[pos:]...)
+	g.Genes[pos] = makeRandomGene(rnd)
+	return strconv.Itoa(pos)
+}
+
+func (deleteMutation) Name() string  { return "delete" }
+func (deleteMutation) PerGene() bool { return false }
+func (deleteMutation) Mutate(g *Genome, _ int, rnd *rand.Rand) string {
+	if len(g.Genes) == 0 {
+		return ""
+	}
+	pos := rnd.Intn(len(g.Genes))
+	g.Genes = append(g.Genes[:pos], g.Genes[pos+1:]...)
+	return strconv.Itoa(pos)
+}
+
+func (neuronMutation) Name() string  { return "neurons" }
+func (neuronMutation) PerGene() bool { return false }
+func (neuronMutation) Mutate(g *Genome, _ int, rnd *rand.Rand) string {
+	change := plusMinusOne(rnd)
+	g.NoOfNeurons += change
+	if g.NoOfNeurons < 0 {
+		g.NoOfNeurons = 0
+	}
+	return fmt.Sprintf("%+d", change)
+}
+
+// duplicateMutation inserts a copy of a gene right after it
+func (duplicateMutation) Name() string  { return "duplicate" }
+func (duplicateMutation) PerGene() bool { return false }
+func (duplicateMutation) Mutate(g *Genome, _ int, rnd *rand.Rand) string {
+	if len(g.Genes) == 0 {
+		return ""
+	}
+	pos := rnd.Intn(len(g.Genes))
+	g.Genes = append(g.Genes[:pos+1], g.Genes[pos:]...)
+	return strconv.Itoa(pos)
+}
+
+// swapMutation lets two genes trade places
+func (swapMutation) Name() string  { return "swap" }
+func (swapMutation) PerGene() bool { return false }
+func (swapMutation) Mutate(g *Genome, _ int, rnd *rand.Rand) string {
+	if len(g.Genes) < 2 {
+		return ""
+	}
+	a := rnd.Intn(len(g.Genes))
+	b := (a + 1 + rnd.Intn(len(g.Genes)-1)) % len(g.Genes)
+	g.Genes[a], g.Genes[b] = g.Genes[b], g.Genes[a]
+	return fmt.Sprintf("%d %d", a, b)
+}
+
+// invertMutation reverses the order of a segment of genes
+func (invertMutation) Name() string  { return "invert" }
+func (invertMutation) PerGene() bool { return false }
+func (invertMutation) Mutate(g *Genome, _ int, rnd *rand.Rand) string {
+	if len(g.Genes) < 2 {
+		return ""
+	}
+	from := rnd.Intn(len(g.Genes) - 1)
+	to := from + 1 + rnd.Intn(len(g.Genes)-from-1)
+	for i, j := from, to; i < j; i, j = i+1, j-1 {
+		g.Genes[i], g.Genes[j] = g.Genes[j], g.Genes[i]
+	}
+	return fmt.Sprintf("%d-%d", from, to)
+}
+
+// MutationOperatorName returns the name of the operator that made the mutation with the given description
+func MutationOperatorName(description string) string {
+	if idx := strings.IndexByte(description, ' '); idx >= 0 {
+		return description[:idx]
+	}
+	return description
+}
+
+func plusMinusOne(rnd *rand.Rand) int {
+	if rnd.Intn(2) == 0 {
+		return -1
+	}
+	return 1
+}
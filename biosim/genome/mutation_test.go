@@ -0,0 +1,127 @@
+package genome
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rnd gives the genomes and mutations in the tests their random numbers
+var rnd = rand.New(rand.NewSource(1))
+
+func TestMutationOperators_StillBuildANet(t *testing.T) {
+	for _, op := range MutationOperators {
+		t.Run(op.Name(), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				genome := MakeRandomGenome(5, rnd)
+				gene := -1
+				if op.PerGene() {
+					gene = i % len(genome.Genes)
+				}
+				description := op.Mutate(&genome, gene, rnd)
+				require.NotEmpty(t, description)
+				require.NotPanics(t, func() { _, _ = genome.BuildNet() }, "%s %s", op.Name(), description)
+			}
+		})
+	}
+}
+
+func TestMutationOperators_ShuffleTheSameGenes(t *testing.T) {
+	for _, op := range []MutationOperator{swapMutation{}, invertMutation{}} {
+		genome := MakeRandomGenome(10, rnd)
+		before := append([]Gene(nil), genome.Genes...)
+		op.Mutate(&genome, -1, rnd)
+		assert.ElementsMatch(t, before, genome.Genes, op.Name())
+	}
+}
+
+func TestDuplicateMutation(t *testing.T) {
+	genome := MakeRandomGenome(3, rnd)
+	description := duplicateMutation{}.Mutate(&genome, -1, rnd)
+	require.Len(t, genome.Genes, 4)
+
+	pos := int(description[0] - '0')
+	assert.Equal(t, genome.Genes[pos], genome.Genes[pos+1])
+}
+
+func TestMutationOperators_NothingToDo(t *testing.T) {
+	for _, op := range []MutationOperator{deleteMutation{}, duplicateMutation{}, swapMutation{}, invertMutation{}} {
+		genome := Genome{}
+		assert.Empty(t, op.Mutate(&genome, -1, rnd), op.Name())
+	}
+}
+
+func TestGenomeClone_LeavesParentAlone(t *testing.T) {
+	parent := MakeRandomGenome(10, rnd)
+	before := append([]Gene(nil), parent.Genes...)
+	rates := MutationRates{}
+	for _, op := range MutationOperators {
+		rates[op.Name()] = 1000
+	}
+
+	child, mutations := parent.Clone(MUTATION_RATE, rates, GenomeBounds{MinGenes: 1, MaxGenes: 100, MaxNeurons: 100}, rnd)
+	assert.NotEmpty(t, mutations)
+	assert.NotEqual(t, before, child.Genes)
+	assert.Equal(t, before, parent.Genes)
+
+	child, mutations = parent.Clone(MUTATION_RATE, MutationRates{}, DefaultGenomeBounds(), rnd)
+	assert.Empty(t, mutations)
+	assert.Equal(t, before, child.Genes)
+}
+
+func TestMutationRates_Set(t *testing.T) {
+	rates := DefaultMutationRates()
+	require.NoError(t, rates.Set("swap=0.5,weight=0"))
+	assert.Equal(t, 0.5, rates["swap"])
+	assert.Equal(t, 0.0, rates["weight"])
+	assert.Equal(t, 1.0, rates["insert"])
+
+	assert.Error(t, rates.Set("teleport=1"))
+	assert.Error(t, rates.Set("swap"))
+	assert.Error(t, rates.Set("swap=often"))
+}
+
+func TestGenomeClone_StaysInBounds(t *testing.T) {
+	bounds := GenomeBounds{MinGenes: 4, MaxGenes: 6, MinNeurons: 1, MaxNeurons: 2}
+	rates := MutationRates{"insert": 1000, "delete": 1000, "duplicate": 1000, "neurons": 1000}
+	genome := bounds.RandomGenome(INITIAL_GENES, rnd)
+	require.True(t, bounds.Allows(genome))
+	for i := 0; i < 200; i++ {
+		genome, _ = genome.Clone(MUTATION_RATE, rates, bounds, rnd)
+		require.True(t, bounds.Allows(genome), "%d genes, %d neurons", len(genome.Genes), genome.NoOfNeurons)
+	}
+}
+
+func TestGenomeBounds_Validate(t *testing.T) {
+	assert.NoError(t, DefaultGenomeBounds().Validate())
+	assert.Error(t, GenomeBounds{MinGenes: 0, MaxGenes: 5}.Validate())
+	assert.Error(t, GenomeBounds{MinGenes: 5, MaxGenes: 4}.Validate())
+	assert.Error(t, GenomeBounds{MinGenes: 1, MaxGenes: 4, MinNeurons: 3, MaxNeurons: 2}.Validate())
+	assert.NoError(t, GenomeBounds{MinGenes: 1, MaxGenes: 4, MaxNeurons: MAX_NEURON_IDS}.Validate())
+	assert.Error(t, GenomeBounds{MinGenes: 1, MaxGenes: 4, MinNeurons: 256, MaxNeurons: 300}.Validate(), "neuron ids are a uint8")
+}
+
+func TestMutationBias(t *testing.T) {
+	// at this rate every gene mutates, unless the bias says otherwise
+	rates := MutationRates{"weight": 1000 / MUTATION_RATE}
+	bounds := DefaultGenomeBounds()
+
+	loud := MakeRandomGenome(10, rnd)
+	_, mutations := loud.Clone(MUTATION_RATE, rates, bounds, rnd)
+	assert.Len(t, mutations, 10)
+
+	quiet := MakeRandomGenome(10, rnd)
+	quiet.MutationBias = -MAX_BIAS
+	_, mutations = quiet.Clone(MUTATION_RATE, rates, bounds, rnd)
+	assert.Less(t, len(mutations), 5)
+
+	// the bias is inherited, and only the bias operator changes it
+	child, _ := quiet.Clone(MUTATION_RATE, rates, bounds, rnd)
+	assert.Equal(t, -MAX_BIAS, int(child.MutationBias))
+	description := biasMutation{}.Mutate(&child, -1, rnd)
+	require.NotEmpty(t, description)
+	assert.LessOrEqual(t, math.Abs(child.MutationBias), float64(MAX_BIAS))
+}
@@ -0,0 +1,94 @@
+package biosim
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// ImmigrationPolicy decides where the immigrants in every new generation come from
+type ImmigrationPolicy uint8
+
+const (
+	// ImmigrationNone fills every new generation with offspring of the survivors only
+	ImmigrationNone ImmigrationPolicy = iota
+
+	// ImmigrationRandom brings in individuals with fresh random genomes
+	ImmigrationRandom
+
+	// ImmigrationPool brings in individuals with genomes picked from a gene pool file
+	ImmigrationPool
+)
+
+const IMMIGRATION_RATE = 0.1
+
+var immigrationNames = map[ImmigrationPolicy]string{
+	ImmigrationNone:   "none",
+	ImmigrationRandom: "random",
+	ImmigrationPool:   "pool",
+}
+
+func (p ImmigrationPolicy) String() string {
+	return immigrationNames[p]
+}
+
+// Set implements flag.Value
+func (p *ImmigrationPolicy) Set(s string) error {
+	for policy, name := range immigrationNames {
+		if name == s {
+			*p = policy
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown immigration policy %q", s)
+}
+
+// immigrantCount is the number of immigrants in a new generation of the given size
+func (s *island) immigrantCount(population int) int {
+	if s.config.Immigration == ImmigrationNone {
+		return 0
+	}
+	n := int(math.Round(s.config.ImmigrationRate * float64(population)))
+	if n > population {
+		n = population
+	}
+	return n
+}
+
+// immigrant creates an individual from outside the population
+func (s *island) immigrant() *world.Individual {
+	if s.config.Immigration == ImmigrationPool {
+		genome := s.genePool[s.rnd.Intn(len(s.genePool))]
+		brain, err := genome.BuildNet()
+		if err != nil {
+			// loadGenePool has made sure every genome builds
+			panic(err)
+		}
+		return world.NewIndividual(s.world, genome, brain, s.rnd)
+	}
+	return world.CreateIndividual(s.world, s.rnd)
+}
+
+// loadGenePool reads the genomes of a checkpoint file, to use for immigration.
+// Genomes outside the bounds, and genomes that are too simple to build a brain from, are left out
+func loadGenePool(filename string, bounds genome.GenomeBounds) ([]genome.Genome, error) {
+	cp, err := readCheckpoint(filename)
+	if err != nil {
+		return nil, err
+	}
+	var pool []genome.Genome
+	for _, genome := range cp.Genomes {
+		if !bounds.Allows(genome) {
+			continue
+		}
+		if _, err := genome.BuildNet(); err == nil {
+			pool = append(pool, genome)
+		}
+	}
+	if len(pool) == 0 {
+		return nil, fmt.Errorf("%s holds no genomes within the bounds that make a working brain", filename)
+	}
+	return pool, nil
+}
@@ -0,0 +1,87 @@
+package biosim
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// immigrationSimulation has a world with room for a full population, and a single survivor to breed from
+func immigrationSimulation(policy ImmigrationPolicy, rate float64) (*island, []*world.Individual) {
+	config := DefaultConfig()
+	config.Immigration = policy
+	config.ImmigrationRate = rate
+	w := &world.World{XSize: 50, YSize: 50, Cells: make([]world.Cell, 2500), Config: config.Config}
+	survivor := world.CreateIndividual(w, rnd)
+	return newIsland(w, config, 1), []*world.Individual{survivor}
+}
+
+// parentless counts the individuals that are not the offspring of anyone
+func parentless(peeps []*world.Individual) int {
+	n := 0
+	for _, peep := range peeps {
+		if len(peep.Parents) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestReproduce_NoImmigration(t *testing.T) {
+	s, survivors := immigrationSimulation(ImmigrationNone, 0.5)
+	assert.Equal(t, 0, s.reproduce(survivors, genome.MUTATION_RATE))
+	assert.Len(t, s.world.Peeps, world.POPULATION)
+	assert.Equal(t, 0, parentless(s.world.Peeps))
+}
+
+func TestReproduce_RandomImmigration(t *testing.T) {
+	s, survivors := immigrationSimulation(ImmigrationRandom, 0.25)
+	assert.Equal(t, world.POPULATION/4, s.reproduce(survivors, genome.MUTATION_RATE))
+	assert.Len(t, s.world.Peeps, world.POPULATION)
+	assert.Equal(t, world.POPULATION/4, parentless(s.world.Peeps))
+}
+
+func TestReproduce_PoolImmigration(t *testing.T) {
+	s, survivors := immigrationSimulation(ImmigrationPool, 0.1)
+	pool := []*world.Individual{world.CreateIndividual(s.world, rnd)}
+	filename := filepath.Join(t.TempDir(), "pool.json")
+	require.NoError(t, writeCheckpoint(filename, 1, pool))
+	var err error
+	s.genePool, err = loadGenePool(filename, s.config.GenomeBounds)
+	require.NoError(t, err)
+
+	assert.Equal(t, world.POPULATION/10, s.reproduce(survivors, genome.MUTATION_RATE))
+	for _, peep := range s.world.Peeps {
+		if len(peep.Parents) == 0 {
+			assert.Equal(t, pool[0].Genome.Genes, peep.Genome.Genes)
+		}
+	}
+}
+
+func TestLoadGenePool_NothingUseful(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "pool.json")
+	require.NoError(t, writeCheckpoint(filename, 1, []*world.Individual{{Genome: genome.Genome{}}}))
+	_, err := loadGenePool(filename, genome.DefaultGenomeBounds())
+	assert.Error(t, err)
+}
+
+func TestLoadGenePool_OutOfBounds(t *testing.T) {
+	w := &world.World{XSize: 50, YSize: 50, Cells: make([]world.Cell, 2500), Config: world.DefaultConfig()}
+	peep := world.CreateIndividual(w, rnd)
+	filename := filepath.Join(t.TempDir(), "pool.json")
+	require.NoError(t, writeCheckpoint(filename, 1, []*world.Individual{peep}))
+
+	bounds := genome.DefaultGenomeBounds()
+	pool, err := loadGenePool(filename, bounds)
+	require.NoError(t, err)
+	assert.Len(t, pool, 1)
+
+	bounds.MaxNeurons = peep.Genome.NoOfNeurons - 1
+	bounds.MinNeurons = 0
+	_, err = loadGenePool(filename, bounds)
+	assert.Error(t, err, "the only genome has too many neurons")
+}
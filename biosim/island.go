@@ -0,0 +1,207 @@
+package biosim
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// island is a world with a population of its own. A simulation has one or more of them
+type island struct {
+	world  *world.World
+	config Config
+
+	// mu is held while the island changes the world. In between, the http server can lock it to look around
+	mu      sync.Mutex
+	resumed *sync.Cond // signalled when a paused island is resumed
+	paused  bool
+
+	generation  int
+	currentStep int // the number of steps of the generation that have been made
+	history     []GenerationStats
+
+	// seed is where the random numbers of the moves come from, see stepRand. rnd is for all other random
+	// numbers of the island, from events and culling to the genomes and places of the next generation.
+	// Only the goroutine holding mu draws from it
+	seed int64
+	rnd  *rand.Rand
+
+	// genePool holds the genomes immigrants are made from when the immigration policy is pool
+	genePool []genome.Genome
+	// immigrants is the number of immigrants in the current generation
+	immigrants int
+
+	// clusters group similar genomes, with shared reproduction
+	clusters []*cluster
+
+	lineage *lineageLog
+
+	// workers think and move for the individuals
+	workers *workerPool
+}
+
+// newIsland makes an island of the world, with its random numbers seeded by seed
+func newIsland(w *world.World, config Config, seed int64) *island {
+	if w.IDs == nil {
+		// an island on its own hands out ids of its own
+		w.IDs = &world.IDs{}
+	}
+	s := &island{
+		world:  w,
+		config: config,
+		seed:   seed,
+		rnd:    rand.New(rand.NewSource(seed)),
+	}
+	s.resumed = sync.NewCond(&s.mu)
+	return s
+}
+
+// step makes the next step of the generation. It returns false, without doing anything, when there are no
+// steps of the generation left
+func (s *island) step() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.paused {
+		s.resumed.Wait()
+	}
+	if s.currentStep >= s.world.StepsPerGeneration {
+		return false
+	}
+	if s.currentStep == 0 && s.config.ResetEachGeneration {
+		for _, peep := range s.world.Peeps {
+			peep.ResetBrain()
+		}
+	}
+	s.world.ApplyEvents(s.generation, s.currentStep, s.rnd)
+	s.thinkAndMove()
+	s.currentStep++
+	return true
+}
+
+// thinkAndMove lets all individuals think, and then move the way their actions say.
+// Both the thinking and the moving are spread over the workers
+func (s *island) thinkAndMove() {
+	if s.workers == nil {
+		s.workers = newWorkerPool(s.config.Workers)
+	}
+	peeps := s.world.Peeps
+	actions := make([]brain.Actions, len(peeps))
+	s.workers.batches(len(peeps), func(from, to int) {
+		for id := from; id < to; id++ {
+			actions[id] = s.world.Think(id)
+		}
+	})
+	for _, phase := range s.world.TilePhases() {
+		s.workers.run(len(phase), func(idx int) {
+			rnd := newStepRand()
+			for _, id := range phase[idx] {
+				rnd.reseed(s.seed, int64(s.generation), int64(s.currentStep), int64(id))
+				s.world.Act(id, actions[id], rnd.Rand)
+			}
+		})
+	}
+}
+
+// endGeneration culls the world, and returns the survivors and the stats of the generation.
+// The caller must hold s.mu
+func (s *island) endGeneration(mutationRate int64) ([]*world.Individual, GenerationStats) {
+	w := s.world
+	if s.config.Reproduction == ReproduceShared {
+		s.clusterPopulation()
+	}
+	stats := GenerationStats{
+		Generation:     s.generation,
+		Population:     len(w.Peeps),
+		ExpressedRatio: world.ExpressionRatio(w.Peeps),
+		Diversity:      diversity(w.Peeps),
+		Immigrants:     s.immigrants,
+		MutationRate:   mutationRate,
+		MutationBias:   meanMutationBias(w.Peeps),
+		Mutations:      countMutations(w.Peeps),
+	}
+	survivors := w.Cull(s.rnd)
+	stats.Survivors = len(survivors)
+	if len(s.config.Species) > 1 {
+		stats.Species = world.CountSpecies(survivors, s.config.Species)
+	}
+	if s.config.Reproduction == ReproduceShared {
+		stats.Clusters = len(s.clusters)
+		stats.ClusterSizes = s.clusterSizes()
+	}
+	stats.SurvivingMutations = countMutations(survivors)
+	stats.BudgetExhausted = atomic.SwapInt64(&w.BudgetExhausted, 0)
+	return survivors, stats
+}
+
+// nextGeneration moves the island on to the first step of the next generation. The caller must hold s.mu
+func (s *island) nextGeneration() {
+	s.generation++
+	s.currentStep = 0
+}
+
+// reproduce fills the world with the offspring of the survivors, and the immigrants the immigration policy lets in.
+// Every species is brought back to its population target, unless it has died out. It returns the number of immigrants
+func (s *island) reproduce(survivors []*world.Individual, mutationRate int64) int {
+	immigrants := 0
+	for species, parents := range world.BySpecies(survivors, len(s.config.Species)) {
+		if len(parents) > 0 {
+			immigrants += s.reproduceSpecies(species, parents, mutationRate)
+		}
+	}
+	return immigrants
+}
+
+func (s *island) reproduceSpecies(species int, survivors []*world.Individual, mutationRate int64) int {
+	w := s.world
+	population := s.config.Species[species].Population
+	immigrants := s.immigrantCount(population)
+	offspring := population - immigrants
+	copies := offspring / len(survivors)
+	born := 0
+
+	if s.config.Reproduction == ReproduceShared {
+		for _, parent := range s.sharedOffspring(survivors, offspring) {
+			clone := parent.Clone(w, mutationRate, s.rnd)
+			clone.Location = w.RandomCoord(s.rnd)
+			s.addChild(clone)
+			born++
+		}
+	}
+
+	// fair distribution of survivors
+	for _, survivor := range survivors {
+		for i := 0; i < copies && born < offspring; i++ {
+			clone := survivor.Clone(w, mutationRate, s.rnd)
+			clone.Location = w.RandomCoord(s.rnd)
+			s.addChild(clone)
+			born++
+		}
+	}
+
+	// random fill up of offspring until we reach the share of the population that is not immigrants
+	for ; born < offspring; born++ {
+		peep := survivors[s.rnd.Intn(len(survivors))]
+		clone := peep.Clone(w, mutationRate, s.rnd)
+		clone.Location = w.RandomCoord(s.rnd)
+		s.addChild(clone)
+	}
+
+	for ; born < population; born++ {
+		immigrant := s.immigrant()
+		immigrant.Species = species
+		s.addChild(immigrant)
+	}
+	return immigrants
+}
+
+// addChild places an individual born for the next generation in the world
+func (s *island) addChild(child *world.Individual) {
+	child.BirthPlace = child.Location
+	child.Born = s.generation + 1
+	s.world.AddPeep(child)
+	s.lineage.birth(child)
+}
@@ -0,0 +1,64 @@
+package biosim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+func BenchmarkThinkAndMove(b *testing.B) {
+	w := &world.World{
+		StepsPerGeneration: world.STEPS_PER_GEN,
+		XSize:              world.SIZE,
+		YSize:              world.SIZE,
+		Cells:              make([]world.Cell, world.SIZE*world.SIZE),
+		Config:             world.DefaultConfig(),
+	}
+	world.FillWithRandomPeeps(w, rnd)
+	s := newIsland(w, DefaultConfig(), 1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.thinkAndMove()
+	}
+}
+
+func TestReproduce_KeepsSpeciesApart(t *testing.T) {
+	config := DefaultConfig()
+	config.Immigration = ImmigrationNone
+	config.Species = world.SpeciesList{{Name: "a", Population: 30}, {Name: "b", Population: 20}, {Name: "c", Population: 10}}
+	w := &world.World{XSize: 50, YSize: 50, Cells: make([]world.Cell, 2500), Config: config.Config}
+	s := newIsland(w, config, 1)
+	a := world.CreateIndividual(w, rnd)
+	b := world.CreateIndividual(w, rnd)
+	b.Species = 1
+
+	s.reproduce([]*world.Individual{a, b}, genome.MUTATION_RATE)
+	counts := world.CountSpecies(w.Peeps, w.Config.Species)
+	assert.Equal(t, map[string]int{"a": 30, "b": 20}, counts, "c has died out, so it stays gone")
+	for _, peep := range w.Peeps {
+		if peep.Species == 0 {
+			assert.Equal(t, []int{a.ID}, peep.Parents)
+		} else {
+			assert.Equal(t, []int{b.ID}, peep.Parents)
+		}
+	}
+}
+
+func TestEndGeneration_CountsSpecies(t *testing.T) {
+	config := DefaultConfig()
+	config.Species = world.SpeciesList{
+		{Name: "prey", Population: 10, Selection: world.SelectAway},
+		{Name: "wolves", Population: 10, Selection: world.SelectNear},
+	}
+	w := world.NewScenarioWorld(config.Config, &world.Scenario{Size: world.Coord{X: 10, Y: 6}, StepsPerGeneration: 10})
+	w.AddPeep(&world.Individual{Species: 0, Location: world.Coord{X: 9}, BirthPlace: world.Coord{X: 9}, Brain: &brain.NeuralNet{}})
+	w.AddPeep(&world.Individual{Species: 1, Location: world.Coord{Y: 3}, BirthPlace: world.Coord{Y: 3}, Brain: &brain.NeuralNet{}})
+	s := newIsland(w, config, 1)
+	s.generation = 1
+
+	_, stats := s.endGeneration(genome.MUTATION_RATE)
+	assert.Equal(t, map[string]int{"prey": 1}, stats.Species)
+}
@@ -0,0 +1,230 @@
+package biosim
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+type (
+	// lineageLog writes every birth and every cull of a run to a file, one JSON record per line.
+	// Errors are remembered and returned when the log is closed. A nil *lineageLog logs nothing
+	lineageLog struct {
+		f   *os.File
+		w   *bufio.Writer
+		enc *json.Encoder
+		err error
+	}
+
+	lineageRecord struct {
+		Birth *BirthRecord `json:"birth,omitempty"`
+		Cull  *cullRecord  `json:"cull,omitempty"`
+	}
+
+	// BirthRecord is the birth of an individual, as it is logged
+	BirthRecord struct {
+		ID         int      `json:"id"`
+		Parents    []int    `json:"parents,omitempty"`
+		Generation int      `json:"generation"`
+		Lineage    int      `json:"lineage"`
+		Mutations  []string `json:"mutations,omitempty"`
+	}
+
+	cullRecord struct {
+		Generation int   `json:"generation"`
+		Survivors  []int `json:"survivors"`
+	}
+
+	// LineageNode is an individual in an ancestry tree
+	LineageNode struct {
+		ID         int            `json:"id"`
+		Generation int            `json:"generation"`
+		Lineage    int            `json:"lineage"`
+		Mutations  []string       `json:"mutations,omitempty"`
+		Survivor   bool           `json:"survivor,omitempty"`
+		Children   []*LineageNode `json:"children,omitempty"`
+	}
+)
+
+func createLineageLog(filename string) (*lineageLog, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	w := bufio.NewWriter(f)
+	return &lineageLog{f: f, w: w, enc: json.NewEncoder(w)}, nil
+}
+
+func (l *lineageLog) write(record lineageRecord) {
+	if l == nil || l.err != nil {
+		return
+	}
+	l.err = l.enc.Encode(record)
+}
+
+func (l *lineageLog) birth(peep *world.Individual) {
+	l.write(lineageRecord{Birth: &BirthRecord{
+		ID:         peep.ID,
+		Parents:    peep.Parents,
+		Generation: peep.Born,
+		Lineage:    peep.Lineage,
+		Mutations:  peep.Mutations,
+	}})
+}
+
+func (l *lineageLog) cull(generation int, survivors []*world.Individual) {
+	ids := make([]int, 0, len(survivors))
+	for _, peep := range survivors {
+		ids = append(ids, peep.ID)
+	}
+	l.write(lineageRecord{Cull: &cullRecord{Generation: generation, Survivors: ids}})
+}
+
+func (l *lineageLog) close() error {
+	if l == nil {
+		return nil
+	}
+	if err := l.w.Flush(); err != nil && l.err == nil {
+		l.err = err
+	}
+	if err := l.f.Close(); err != nil && l.err == nil {
+		l.err = err
+	}
+	return l.err
+}
+
+// ReadLineageLog reads the births of a lineage log, and the survivors of the given generation.
+// A negative generation picks the last generation that was culled
+func ReadLineageLog(r io.Reader, generation int) (map[int]*BirthRecord, []int, error) {
+	births := map[int]*BirthRecord{}
+	var survivors []int
+	found := false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var record lineageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, nil, err
+		}
+		switch {
+		case record.Birth != nil:
+			births[record.Birth.ID] = record.Birth
+		case record.Cull != nil:
+			if generation < 0 || record.Cull.Generation == generation {
+				survivors = record.Cull.Survivors
+				found = true
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, fmt.Errorf("no cull found for generation %d", generation)
+	}
+	return births, survivors, nil
+}
+
+// Ancestry builds the family trees of the survivors, leaving out everyone who has no surviving descendant.
+// Individuals with more than one parent are placed under their first parent.
+// The roots are randomly created individuals, or the oldest ancestors the log knows about
+func Ancestry(births map[int]*BirthRecord, survivors []int) ([]*LineageNode, error) {
+	nodes := map[int]*LineageNode{}
+	var roots []*LineageNode
+
+	// node returns the tree node of an individual, and whether it was already part of the tree
+	node := func(id int) (*LineageNode, bool, error) {
+		if n, ok := nodes[id]; ok {
+			return n, true, nil
+		}
+		birth, ok := births[id]
+		if !ok {
+			return nil, false, fmt.Errorf("individual %d is not in the lineage log", id)
+		}
+		n := &LineageNode{
+			ID:         id,
+			Generation: birth.Generation,
+			Lineage:    birth.Lineage,
+			Mutations:  birth.Mutations,
+		}
+		nodes[id] = n
+		return n, false, nil
+	}
+
+	for _, id := range survivors {
+		current, existed, err := node(id)
+		if err != nil {
+			return nil, err
+		}
+		current.Survivor = true
+		// walk up towards the root, until we reach a part of the tree that is already built
+		for !existed {
+			parents := births[current.ID].Parents
+			if len(parents) == 0 || births[parents[0]] == nil {
+				roots = append(roots, current)
+				break
+			}
+			var parent *LineageNode
+			parent, existed, err = node(parents[0])
+			if err != nil {
+				return nil, err
+			}
+			parent.Children = append(parent.Children, current)
+			current = parent
+		}
+	}
+
+	sortNodes(roots)
+	return roots, nil
+}
+
+func sortNodes(nodes []*LineageNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
+
+// Newick writes the trees in the Newick format. Nodes are labeled with their id, and branch lengths are
+// the number of generations between parent and child
+func Newick(roots []*LineageNode) string {
+	var sb strings.Builder
+	var write func(n *LineageNode, parentGeneration int)
+	write = func(n *LineageNode, parentGeneration int) {
+		if len(n.Children) > 0 {
+			sb.WriteString("(")
+			for idx, child := range n.Children {
+				if idx > 0 {
+					sb.WriteString(",")
+				}
+				write(child, n.Generation)
+			}
+			sb.WriteString(")")
+		}
+		fmt.Fprintf(&sb, "%d:%d", n.ID, n.Generation-parentGeneration)
+	}
+
+	if len(roots) == 1 {
+		write(roots[0], 0)
+	} else {
+		// a Newick file holds a single tree, so several roots get a common root without label
+		sb.WriteString("(")
+		for idx, root := range roots {
+			if idx > 0 {
+				sb.WriteString(",")
+			}
+			write(root, 0)
+		}
+		sb.WriteString(")")
+	}
+	sb.WriteString(";\n")
+	return sb.String()
+}
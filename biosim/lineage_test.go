@@ -0,0 +1,77 @@
+package biosim
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// writeTestLineage logs two random individuals, 1 and 2. 1 gets children 3 and 4, 3 gets child 5.
+// After generation 1, 2 and 4 survive, and after generation 2, 4 and 5 survive
+func writeTestLineage(t *testing.T) string {
+	filename := filepath.Join(t.TempDir(), "lineage.jsonl")
+	l, err := createLineageLog(filename)
+	require.NoError(t, err)
+
+	p := func(id, parent, born int, mutations ...string) *world.Individual {
+		peep := &world.Individual{ID: id, Born: born, Lineage: 1, Mutations: mutations}
+		if parent != 0 {
+			peep.Parents = []int{parent}
+		}
+		l.birth(peep)
+		return peep
+	}
+	p1, p2 := p(1, 0, 0), p(2, 0, 0)
+	l.cull(0, []*world.Individual{p1, p2})
+	p3, p4 := p(3, 1, 1, "weight 1"), p(4, 1, 1)
+	l.cull(1, []*world.Individual{p3, p4})
+	p5 := p(5, 3, 2, "insert 0")
+	l.cull(2, []*world.Individual{p4, p5})
+
+	require.NoError(t, l.close())
+	return filename
+}
+
+// trace reads the test lineage, and builds the ancestry of the survivors of the generation
+func trace(t *testing.T, generation int) []*LineageNode {
+	f, err := os.Open(writeTestLineage(t))
+	require.NoError(t, err)
+	defer f.Close()
+	births, survivors, err := ReadLineageLog(f, generation)
+	require.NoError(t, err)
+	roots, err := Ancestry(births, survivors)
+	require.NoError(t, err)
+	return roots
+}
+
+func TestLineageNewick(t *testing.T) {
+	assert.Equal(t, "((5:1)3:1,4:1)1:0;\n", Newick(trace(t, -1)))
+	assert.Equal(t, "(1:0,2:0);\n", Newick(trace(t, 0)))
+}
+
+func TestLineageJSON(t *testing.T) {
+	data, err := json.Marshal(trace(t, -1))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{
+		"id": 1, "generation": 0, "lineage": 1,
+		"children": [
+			{"id": 3, "generation": 1, "lineage": 1, "mutations": ["weight 1"], "children": [
+				{"id": 5, "generation": 2, "lineage": 1, "mutations": ["insert 0"], "survivor": true}
+			]},
+			{"id": 4, "generation": 1, "lineage": 1, "survivor": true}
+		]
+	}]`, string(data))
+}
+
+func TestReadLineageLog_MissingGeneration(t *testing.T) {
+	f, err := os.Open(writeTestLineage(t))
+	require.NoError(t, err)
+	defer f.Close()
+	_, _, err = ReadLineageLog(f, 7)
+	assert.Error(t, err)
+}
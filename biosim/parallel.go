@@ -0,0 +1,62 @@
+package biosim
+
+import (
+	"runtime"
+	"sync"
+)
+
+// workerPool runs jobs on a fixed number of goroutines. The islands share one pool
+type workerPool struct {
+	jobs chan func()
+}
+
+const BATCH_SIZE = 256 // number of individuals a worker thinks for in one job
+
+func newWorkerPool(workers int) *workerPool {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	p := &workerPool{jobs: make(chan func())}
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range p.jobs {
+				job()
+			}
+		}()
+	}
+	return p
+}
+
+// close stops the workers. The pool can't be used after this
+func (p *workerPool) close() {
+	close(p.jobs)
+}
+
+// run calls job for every index from 0 up to n on the workers, and waits for all of them to finish
+func (p *workerPool) run(n int, job func(idx int)) {
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for idx := 0; idx < n; idx++ {
+		idx := idx
+		p.jobs <- func() {
+			defer wg.Done()
+			job(idx)
+		}
+	}
+	wg.Wait()
+}
+
+// batches splits the indexes from 0 up to n into batches of BATCH_SIZE, and runs them on the workers
+func (p *workerPool) batches(n int, batch func(from, to int)) {
+	p.run((n+BATCH_SIZE-1)/BATCH_SIZE, func(idx int) {
+		from := idx * BATCH_SIZE
+		batch(from, min(from+BATCH_SIZE, n))
+	})
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,51 @@
+package biosim
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+func TestWorkerPoolBatches(t *testing.T) {
+	pool := newWorkerPool(4)
+	seen := make([]int32, 3*BATCH_SIZE+7)
+	pool.batches(len(seen), func(from, to int) {
+		for idx := from; idx < to; idx++ {
+			atomic.AddInt32(&seen[idx], 1)
+		}
+	})
+	for idx, n := range seen {
+		assert.Equal(t, int32(1), n, "index %d", idx)
+	}
+}
+
+func TestStepKeepsCellsConsistent(t *testing.T) {
+	w := &world.World{
+		StepsPerGeneration: 10,
+		XSize:              100,
+		YSize:              100,
+		Cells:              make([]world.Cell, 100*100),
+		Config:             world.DefaultConfig(),
+	}
+	w.Config.Species = world.SpeciesList{{Name: "peeps", Population: 2000, Selection: world.SelectArea}}
+	world.FillWithRandomPeeps(w, rnd)
+	config := DefaultConfig()
+	config.Config = w.Config
+	s := newIsland(w, config, 1)
+	for step := 0; step < 10; step++ {
+		s.step()
+	}
+
+	occupied := 0
+	for _, cell := range w.Cells {
+		if cell != world.EMPTY && cell != world.BARRIER {
+			occupied++
+		}
+	}
+	assert.Equal(t, len(w.Peeps), occupied)
+	for _, peep := range w.Peeps {
+		assert.Same(t, peep, w.PeepAt(peep.Location.X, peep.Location.Y))
+	}
+}
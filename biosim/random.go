@@ -0,0 +1,48 @@
+package biosim
+
+import "math/rand"
+
+// stepRand hands out the random numbers of the moves. They only depend on the seed of the island, the generation,
+// the step and who moves, so the outcome of a step is the same no matter which worker moves who, and in which order
+type stepRand struct {
+	*rand.Rand
+}
+
+// splitMix is the SplitMix64 generator. It is much cheaper to seed than the source of math/rand, which matters
+// since stepRand is seeded again for every individual in every step
+type splitMix struct {
+	state uint64
+}
+
+func newStepRand() stepRand {
+	return stepRand{rand.New(&splitMix{})}
+}
+
+// reseed makes the numbers that follow depend on the values, and nothing else
+func (r stepRand) reseed(values ...int64) {
+	var s splitMix
+	for _, value := range values {
+		s.state ^= uint64(value)
+		s.state = s.Uint64()
+	}
+	r.Seed(int64(s.state))
+}
+
+// Seed implements rand.Source
+func (s *splitMix) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+// Uint64 implements rand.Source64
+func (s *splitMix) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+// Int63 implements rand.Source
+func (s *splitMix) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
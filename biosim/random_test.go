@@ -0,0 +1,25 @@
+package biosim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepRand(t *testing.T) {
+	draw := func(values ...int64) []int {
+		r := newStepRand()
+		r.reseed(values...)
+		return []int{r.Intn(1000), r.Intn(1000), r.Intn(1000)}
+	}
+	assert.Equal(t, draw(1, 2, 3, 4), draw(1, 2, 3, 4))
+	assert.NotEqual(t, draw(1, 2, 3, 4), draw(1, 2, 3, 5))
+	assert.NotEqual(t, draw(1, 2, 3, 4), draw(1, 2, 4, 3), "the order of the values matters")
+
+	// reseeding starts over, whatever was drawn before
+	r := newStepRand()
+	r.reseed(7)
+	r.Float64()
+	r.reseed(1, 2, 3, 4)
+	assert.Equal(t, draw(1, 2, 3, 4), []int{r.Intn(1000), r.Intn(1000), r.Intn(1000)})
+}
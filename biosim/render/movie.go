@@ -0,0 +1,293 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+type (
+	// OutputFormat decides how the frames of a dumped generation are written
+	OutputFormat uint8
+
+	// MovieWriter receives the frames of a generation, in order
+	MovieWriter interface {
+		AddFrame(step int, img image.Image) error
+		// Close finishes the movie. No frames can be added after this
+		Close() error
+	}
+
+	// pngFrames writes every frame as a separate PNG file into a directory, to be assembled by ffmpeg later.
+	// The files are numbered in the order the frames come in, without gaps, so steps that were left out
+	// don't stop ffmpeg short
+	pngFrames struct {
+		directory string
+		frames    int
+	}
+
+	// gifMovie collects the frames and writes them as an animated GIF when closed
+	gifMovie struct {
+		filename string
+		anim     gif.GIF
+	}
+
+	// apngMovie collects encoded frames and writes them as an animated PNG when closed
+	apngMovie struct {
+		filename string
+		header   []byte   // the IHDR chunk data of the first frame
+		frames   [][]byte // the concatenated IDAT data of every frame
+		width    int
+		height   int
+	}
+)
+
+const (
+	OutputPNG OutputFormat = iota
+	OutputGIF
+	OutputAPNG
+)
+
+// FRAMES_PER_SECOND is the speed the movies are played at
+const FRAMES_PER_SECOND = 25
+
+var outputNames = map[OutputFormat]string{
+	OutputPNG:  "png",
+	OutputGIF:  "gif",
+	OutputAPNG: "apng",
+}
+
+func (o OutputFormat) String() string {
+	return outputNames[o]
+}
+
+// Set implements flag.Value
+func (o *OutputFormat) Set(s string) error {
+	for output, name := range outputNames {
+		if name == s {
+			*o = output
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format %q", s)
+}
+
+func NewMovieWriter(format OutputFormat, generation int) (MovieWriter, error) {
+	switch format {
+	case OutputGIF:
+		return &gifMovie{filename: fmt.Sprintf("%04d.gif", generation)}, nil
+	case OutputAPNG:
+		return &apngMovie{filename: fmt.Sprintf("%04d.png", generation)}, nil
+	default:
+		directory := fmt.Sprintf("%04d", generation)
+		if err := mkdirIfNotExists(directory); err != nil {
+			return nil, err
+		}
+		return &pngFrames{directory: directory}, nil
+	}
+}
+
+func (p *pngFrames) AddFrame(_ int, img image.Image) error {
+	f, err := os.Create(fmt.Sprintf("%s/image%03d.png", p.directory, p.frames))
+	if err != nil {
+		return err
+	}
+	p.frames++
+
+	if err := png.Encode(f, img); err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+func (p *pngFrames) Close() error {
+	return nil
+}
+
+func (g *gifMovie) AddFrame(_ int, img image.Image) error {
+	frame := image.NewPaletted(img.Bounds(), palette.Plan9)
+	draw.Draw(frame, frame.Rect, img, img.Bounds().Min, draw.Src)
+	g.anim.Image = append(g.anim.Image, frame)
+	g.anim.Delay = append(g.anim.Delay, 100/FRAMES_PER_SECOND)
+	return nil
+}
+
+func (g *gifMovie) Close() error {
+	f, err := os.Create(g.filename)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, &g.anim); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (a *apngMovie) AddFrame(_ int, img image.Image) error {
+	bounds := img.Bounds()
+	if a.frames == nil {
+		a.width, a.height = bounds.Dx(), bounds.Dy()
+	} else if bounds.Dx() != a.width || bounds.Dy() != a.height {
+		return fmt.Errorf("frame size %dx%d differs from movie size %dx%d", bounds.Dx(), bounds.Dy(), a.width, a.height)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+
+	// an APNG is a regular PNG with extra chunks, so we encode every frame as a PNG and keep the image data
+	var data []byte
+	var err error
+	readErr := readPNGChunks(buf.Bytes(), func(typ string, chunk []byte) {
+		switch typ {
+		case "IHDR":
+			if a.header == nil {
+				a.header = chunk
+			} else if !bytes.Equal(a.header, chunk) {
+				err = fmt.Errorf("frame is encoded differently from the first frame")
+			}
+		case "IDAT":
+			data = append(data, chunk...)
+		}
+	})
+	if readErr != nil {
+		return readErr
+	}
+	if err != nil {
+		return err
+	}
+	a.frames = append(a.frames, data)
+	return nil
+}
+
+func (a *apngMovie) Close() error {
+	if len(a.frames) == 0 {
+		return nil
+	}
+	f, err := os.Create(a.filename)
+	if err != nil {
+		return err
+	}
+	if err := a.write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (a *apngMovie) write(w io.Writer) error {
+	if _, err := w.Write([]byte("\x89PNG\r\n\x1a\n")); err != nil {
+		return err
+	}
+	if err := writePNGChunk(w, "IHDR", a.header); err != nil {
+		return err
+	}
+
+	// animation control: number of frames, and 0 to loop forever
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(a.frames)))
+	if err := writePNGChunk(w, "acTL", actl); err != nil {
+		return err
+	}
+
+	var sequence uint32
+	for idx, frame := range a.frames {
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], sequence)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(a.width))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(a.height))
+		// x and y offsets stay 0
+		binary.BigEndian.PutUint16(fctl[20:], 1)
+		binary.BigEndian.PutUint16(fctl[22:], FRAMES_PER_SECOND)
+		// dispose and blend ops stay 0, every frame replaces the whole image
+		sequence++
+		if err := writePNGChunk(w, "fcTL", fctl); err != nil {
+			return err
+		}
+
+		if idx == 0 {
+			// the first frame doubles as the still image for viewers that don't know about APNG
+			if err := writePNGChunk(w, "IDAT", frame); err != nil {
+				return err
+			}
+			continue
+		}
+		fdat := make([]byte, 4, 4+len(frame))
+		binary.BigEndian.PutUint32(fdat, sequence)
+		sequence++
+		if err := writePNGChunk(w, "fdAT", append(fdat, frame...)); err != nil {
+			return err
+		}
+	}
+
+	return writePNGChunk(w, "IEND", nil)
+}
+
+// readPNGChunks calls f for every chunk in the encoded PNG
+func readPNGChunks(data []byte, f func(typ string, chunk []byte)) error {
+	const signatureLength = 8
+	if len(data) < signatureLength {
+		return fmt.Errorf("not a png")
+	}
+	data = data[signatureLength:]
+	for len(data) > 0 {
+		if len(data) < 12 {
+			return fmt.Errorf("truncated png chunk")
+		}
+		length := int(binary.BigEndian.Uint32(data))
+		if len(data) < 12+length {
+			return fmt.Errorf("truncated png chunk")
+		}
+		f(string(data[4:8]), data[8:8+length])
+		data = data[12+length:]
+	}
+	return nil
+}
+
+func writePNGChunk(w io.Writer, typ string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], typ)
+
+	crc := crc32.NewIEEE()
+	_, _ = crc.Write(header[4:])
+	_, _ = crc.Write(data)
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+
+	for _, part := range [][]byte{header, data, footer} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mkdirIfNotExists(directory string) error {
+	err := os.Mkdir(directory, os.ModePerm)
+	if err != nil {
+		pathErr, ok := err.(*fs.PathError)
+		if ok {
+			sysErr, ok := pathErr.Err.(syscall.Errno)
+			if ok {
+				if sysErr == syscall.EEXIST {
+					return nil
+				}
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPNGFrames(t *testing.T) {
+	w := testWorld()
+	directory := t.TempDir()
+	movie := &pngFrames{directory: directory}
+	// every fifth step, like with -frame-every 5
+	for step := 0; step < 15; step += 5 {
+		require.NoError(t, movie.AddFrame(step, RenderFrame(w, w.Cells, testColors, 1)))
+	}
+	require.NoError(t, movie.Close())
+
+	files, err := filepath.Glob(filepath.Join(directory, "*.png"))
+	require.NoError(t, err)
+	var names []string
+	for _, file := range files {
+		names = append(names, filepath.Base(file))
+	}
+	assert.Equal(t, []string{"image000.png", "image001.png", "image002.png"}, names, "numbered without gaps")
+}
+
+func TestGIFMovie(t *testing.T) {
+	w := testWorld()
+	filename := filepath.Join(t.TempDir(), "movie.gif")
+	movie := &gifMovie{filename: filename}
+	for step := 0; step < 3; step++ {
+		w.Cells[w.OffsetXY(7, step)] = 1
+		require.NoError(t, movie.AddFrame(step, RenderFrame(w, w.Cells, testColors, 1)))
+	}
+	require.NoError(t, movie.Close())
+
+	f, err := os.Open(filename)
+	require.NoError(t, err)
+	defer f.Close()
+	anim, err := gif.DecodeAll(f)
+	require.NoError(t, err)
+	assert.Len(t, anim.Image, 3)
+	assert.Equal(t, []int{4, 4, 4}, anim.Delay)
+}
+
+func TestAPNGMovie(t *testing.T) {
+	w := testWorld()
+	movie := &apngMovie{}
+	var first image.Image
+	for step := 0; step < 3; step++ {
+		w.Cells[w.OffsetXY(7, step)] = 1
+		frame := RenderFrame(w, w.Cells, testColors, 1)
+		if first == nil {
+			first = frame
+		}
+		require.NoError(t, movie.AddFrame(step, frame))
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, movie.write(&buf))
+
+	var chunks []string
+	require.NoError(t, readPNGChunks(buf.Bytes(), func(typ string, _ []byte) {
+		chunks = append(chunks, typ)
+	}))
+	assert.Equal(t, []string{"IHDR", "acTL", "fcTL", "IDAT", "fcTL", "fdAT", "fcTL", "fdAT", "IEND"}, chunks)
+
+	// viewers that don't know APNG see the first frame
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, first.At(7, 0), color.NRGBAModel.Convert(img.At(7, 0)))
+	assert.Equal(t, first.At(7, 1), color.NRGBAModel.Convert(img.At(7, 1)))
+
+	assert.Error(t, movie.AddFrame(3, RenderFrame(w, w.Cells, testColors, 2)))
+}
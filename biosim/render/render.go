@@ -0,0 +1,192 @@
+// Package render draws worlds as images, movies and in the terminal
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// Coloring decides what the color of an individual in a frame tells us
+type Coloring uint8
+
+const (
+	ColorBlack   Coloring = iota // every individual is black
+	ColorGenome                  // individuals with similar genomes get the same color, like in biosim4
+	ColorLineage                 // every individual gets the color of the random ancestor its lineage started with
+	ColorAge                     // from light for the newborn to dark for the old
+	ColorAction                  // the color of the action the individual did most of in the last step
+	ColorSpecies                 // every species has a color of its own
+)
+
+var (
+	survivalColor = color.RGBA{R: 0, G: 255, B: 0, A: 0xff}
+	barrierColor  = color.RGBA{R: 200, G: 200, B: 200, A: 0xff}
+	peepColor     = color.RGBA{A: 0xff}
+
+	actionColors = map[brain.Action]color.RGBA{
+		brain.MOVE_X:      {R: 0xd0, G: 0x20, B: 0x20, A: 0xff},
+		brain.MOVE_Y:      {R: 0x20, G: 0x20, B: 0xd0, A: 0xff},
+		brain.MOVE_RANDOM: {R: 0xd0, G: 0x90, B: 0x00, A: 0xff},
+	}
+
+	coloringNames = map[Coloring]string{
+		ColorBlack:   "black",
+		ColorGenome:  "genome",
+		ColorLineage: "lineage",
+		ColorAge:     "age",
+		ColorAction:  "action",
+		ColorSpecies: "species",
+	}
+)
+
+func (c Coloring) String() string {
+	return coloringNames[c]
+}
+
+// Set implements flag.Value
+func (c *Coloring) Set(s string) error {
+	for coloring, name := range coloringNames {
+		if name == s {
+			*c = coloring
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown coloring %q", s)
+}
+
+// PeepColors returns the color of every individual in the world, indexed by their id
+func PeepColors(w *world.World, coloring Coloring) []color.RGBA {
+	colors := make([]color.RGBA, len(w.Peeps))
+	for id, peep := range w.Peeps {
+		switch coloring {
+		case ColorGenome:
+			colors[id] = genomeColor(peep.Genome)
+		case ColorLineage:
+			colors[id] = lineageColor(peep.Lineage)
+		case ColorAge:
+			old := math.Min(float64(peep.Age)/float64(w.StepsPerGeneration), 1)
+			colors[id] = hsv(0, 0, 0.8-0.8*old)
+		case ColorSpecies:
+			colors[id] = lineageColor(peep.Species + 1)
+		case ColorAction:
+			if c, ok := actionColors[peep.DominantAction]; ok {
+				colors[id] = c
+			} else {
+				colors[id] = peepColor
+			}
+		default:
+			colors[id] = peepColor
+		}
+	}
+	return colors
+}
+
+// genomeColor hashes the genome into a color. Like biosim4, only the first and the last gene are used,
+// so that related genomes get the same color even after a few mutations
+func genomeColor(g genome.Genome) color.RGBA {
+	if len(g.Genes) == 0 {
+		return peepColor
+	}
+	first, last := g.Genes[0], g.Genes[len(g.Genes)-1]
+	bit := func(b bool) uint8 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+	c := bit(first.SourceIsSensor) |
+		bit(last.SourceIsSensor)<<1 |
+		bit(first.SinkIsAction)<<2 |
+		bit(last.SinkIsAction)<<3 |
+		(first.SourceID&1)<<4 |
+		(first.SinkID&1)<<5 |
+		(last.SourceID&1)<<6 |
+		(last.SinkID&1)<<7
+	return hsv(float64(c)/256, 0.9, 0.4+0.4*float64(c&3)/3)
+}
+
+// lineageColor spreads lineage ids evenly over the color wheel
+func lineageColor(lineage int) color.RGBA {
+	const goldenRatio = 0.618033988749895
+	hue := math.Mod(float64(lineage)*goldenRatio, 1)
+	return hsv(hue, 0.9, 0.7)
+}
+
+// hsv converts hue, saturation and value, all between 0 and 1, to an opaque color
+func hsv(h, s, v float64) color.RGBA {
+	i := math.Floor(h * 6)
+	f := h*6 - i
+	p := v * (1 - s)
+	q := v * (1 - f*s)
+	t := v * (1 - (1-f)*s)
+	var r, g, b float64
+	switch int(i) % 6 {
+	case 0:
+		r, g, b = v, t, p
+	case 1:
+		r, g, b = q, v, p
+	case 2:
+		r, g, b = p, v, t
+	case 3:
+		r, g, b = p, q, v
+	case 4:
+		r, g, b = t, p, v
+	default:
+		r, g, b = v, p, q
+	}
+	return color.RGBA{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 0xff}
+}
+
+// RenderFrame draws the cells of the world, scaled by the given factor.
+// colors holds the color of every individual, indexed by their id
+func RenderFrame(w *world.World, cells []world.Cell, colors []color.RGBA, scale float64) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w.XSize, w.YSize))
+	for x := 0; x < w.XSize; x++ {
+		for y := 0; y < w.YSize; y++ {
+			offset := w.OffsetXY(x, y)
+			switch cells[offset] {
+			case world.EMPTY:
+				if w.InArea(w.SurvivalArea, x, y) {
+					img.Set(x, y, survivalColor)
+				} else {
+					img.Set(x, y, color.White)
+				}
+			case world.BARRIER:
+				img.Set(x, y, barrierColor)
+			default: // here is an individual
+				img.Set(x, y, colors[world.PeepID(cells[offset])])
+			}
+		}
+	}
+	return scaleImage(img, scale)
+}
+
+// scaleImage resizes the image using nearest neighbour sampling, which keeps single cells crisp
+func scaleImage(img *image.NRGBA, scale float64) *image.NRGBA {
+	if scale <= 0 || scale == 1 {
+		return img
+	}
+	bounds := img.Bounds()
+	width := max(1, int(float64(bounds.Dx())*scale))
+	height := max(1, int(float64(bounds.Dy())*scale))
+	result := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			result.Set(x, y, img.At(int(float64(x)/scale), int(float64(y)/scale)))
+		}
+	}
+	return result
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// rnd gives the tests their random numbers
+var rnd = rand.New(rand.NewSource(1))
+
+func testWorld() *world.World {
+	return world.NewScenarioWorld(world.DefaultConfig(), &world.Scenario{
+		Size:               world.Coord{X: 10, Y: 6},
+		StepsPerGeneration: 10,
+		SurvivalArea:       world.Area{BottomRight: world.Coord{X: 2, Y: 6}},
+		Barriers:           []world.Area{{TopLeft: world.Coord{X: 5}, BottomRight: world.Coord{X: 6, Y: 3}}},
+	})
+}
+
+// testColors colors the individuals with id 0 and 1 black
+var testColors = []color.RGBA{peepColor, peepColor}
+
+func TestRenderFrame(t *testing.T) {
+	w := testWorld()
+	w.Cells[w.OffsetXY(8, 4)] = 1
+
+	img := RenderFrame(w, w.Cells, testColors, 1)
+	assert.Equal(t, image.Rect(0, 0, 10, 6), img.Bounds())
+	assert.Equal(t, color.NRGBAModel.Convert(survivalColor), img.At(1, 5))
+	assert.Equal(t, color.NRGBAModel.Convert(barrierColor), img.At(5, 2))
+	assert.Equal(t, color.NRGBAModel.Convert(color.Black), img.At(8, 4))
+	assert.Equal(t, color.NRGBAModel.Convert(color.White), img.At(9, 5))
+
+	img = RenderFrame(w, w.Cells, testColors, 2)
+	assert.Equal(t, image.Rect(0, 0, 20, 12), img.Bounds())
+	assert.Equal(t, color.NRGBAModel.Convert(color.Black), img.At(17, 9))
+
+	img = RenderFrame(w, w.Cells, testColors, 0.5)
+	assert.Equal(t, image.Rect(0, 0, 5, 3), img.Bounds())
+}
+
+func TestGenomeColor(t *testing.T) {
+	g := genome.MakeRandomGenome(5, rnd)
+	before := genomeColor(g)
+
+	// genes in the middle don't change the color
+	g.Genes[2].Weight++
+	g.Genes[2].SourceID++
+	assert.Equal(t, before, genomeColor(g))
+
+	assert.Equal(t, peepColor, genomeColor(genome.Genome{}))
+}
+
+func TestPeepColors(t *testing.T) {
+	w := &world.World{StepsPerGeneration: 10}
+	w.Peeps = []*world.Individual{
+		{Lineage: 1, Age: 0, DominantAction: brain.MOVE_Y, Genome: genome.MakeRandomGenome(3, rnd)},
+		{Lineage: 2, Age: 10, DominantAction: brain.NUM_ACTIONS, Genome: genome.MakeRandomGenome(3, rnd)},
+	}
+
+	assert.Equal(t, []color.RGBA{peepColor, peepColor}, PeepColors(w, ColorBlack))
+	assert.Equal(t, []color.RGBA{genomeColor(w.Peeps[0].Genome), genomeColor(w.Peeps[1].Genome)}, PeepColors(w, ColorGenome))
+	assert.Equal(t, []color.RGBA{actionColors[brain.MOVE_Y], peepColor}, PeepColors(w, ColorAction))
+
+	lineage := PeepColors(w, ColorLineage)
+	assert.NotEqual(t, lineage[0], lineage[1])
+
+	age := PeepColors(w, ColorAge)
+	assert.Greater(t, age[0].R, age[1].R)
+	assert.Equal(t, peepColor, age[1])
+}
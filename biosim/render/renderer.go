@@ -0,0 +1,151 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+type (
+	// Renderer turns world snapshots into movie frames in the background.
+	// A fixed number of workers render frames concurrently, but the frames reach their movie in the order
+	// they were submitted. At most queueSize frames are waiting at any time; when the queue is full,
+	// submitting blocks until the writers catch up, so memory use stays bounded.
+	Renderer struct {
+		scale float64
+
+		// every job goes through ordered, which is read by a single goroutine that hands the frames to the
+		// movies in submission order. frames also go through work, where the workers pick them up
+		ordered chan *renderJob
+		work    chan *renderJob
+
+		// counts the jobs that have been submitted but not yet handed to their movie
+		pending sync.WaitGroup
+
+		mu  sync.Mutex
+		err error // the first error a movie returned
+
+		started time.Time
+		frames  int64 // updated concurrently, so only touch it through sync/atomic
+	}
+
+	renderJob struct {
+		movie MovieWriter
+		step  int
+
+		// world is a copy of the world with its own cells, or nil when this job closes the movie
+		world  *world.World
+		colors []color.RGBA
+
+		img  *image.NRGBA
+		done chan struct{} // closed when img is ready
+	}
+)
+
+// NewRenderer starts the workers and the writer. Less than 1 worker means one per cpu
+func NewRenderer(workers, queueSize int, scale float64) *Renderer {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	r := &Renderer{
+		scale:   scale,
+		ordered: make(chan *renderJob, queueSize),
+		work:    make(chan *renderJob, queueSize),
+		started: time.Now(),
+	}
+	for i := 0; i < workers; i++ {
+		go r.renderFrames()
+	}
+	go r.writeFrames()
+	return r
+}
+
+// Frame queues a frame of the world as it looks right now. It blocks if the queue is full
+func (r *Renderer) Frame(movie MovieWriter, step int, w *world.World, colors []color.RGBA) {
+	snapshot := &world.World{
+		XSize:        w.XSize,
+		YSize:        w.YSize,
+		SurvivalArea: w.SurvivalArea,
+		Config:       w.Config,
+		Cells:        make([]world.Cell, len(w.Cells)),
+	}
+	copy(snapshot.Cells, w.Cells)
+
+	job := &renderJob{
+		movie:  movie,
+		step:   step,
+		world:  snapshot,
+		colors: colors,
+		done:   make(chan struct{}),
+	}
+	r.pending.Add(1)
+	r.ordered <- job
+	r.work <- job
+}
+
+// Finish closes the movie once all frames submitted before have been added to it
+func (r *Renderer) Finish(movie MovieWriter) {
+	job := &renderJob{
+		movie: movie,
+		done:  make(chan struct{}),
+	}
+	close(job.done)
+	r.pending.Add(1)
+	r.ordered <- job
+}
+
+// flush waits until every submitted frame has been written, and returns the first error the movies reported
+func (r *Renderer) flush() error {
+	r.pending.Wait()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Close flushes, and then stops the workers. The renderer can't be used after this
+func (r *Renderer) Close() error {
+	err := r.flush()
+	close(r.work)
+	close(r.ordered)
+	return err
+}
+
+// Throughput reports the number of frames written, and how many frames per second that has been
+func (r *Renderer) Throughput() string {
+	frames := atomic.LoadInt64(&r.frames)
+	return fmt.Sprintf("%d (%.1f/s)", frames, float64(frames)/time.Since(r.started).Seconds())
+}
+
+func (r *Renderer) renderFrames() {
+	for job := range r.work {
+		job.img = RenderFrame(job.world, job.world.Cells, job.colors, r.scale)
+		close(job.done)
+	}
+}
+
+func (r *Renderer) writeFrames() {
+	for job := range r.ordered {
+		<-job.done
+		var err error
+		if job.world == nil {
+			err = job.movie.Close()
+		} else {
+			err = job.movie.AddFrame(job.step, job.img)
+			atomic.AddInt64(&r.frames, 1)
+		}
+		if err != nil {
+			r.mu.Lock()
+			if r.err == nil {
+				r.err = err
+			}
+			r.mu.Unlock()
+		}
+		r.pending.Done()
+	}
+}
@@ -0,0 +1,78 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMovie remembers the order things happened to it
+type recordingMovie struct {
+	events []string
+	fail   bool
+}
+
+func (m *recordingMovie) AddFrame(step int, img image.Image) error {
+	m.events = append(m.events, fmt.Sprintf("frame %d %dx%d", step, img.Bounds().Dx(), img.Bounds().Dy()))
+	if m.fail {
+		return fmt.Errorf("disk full")
+	}
+	return nil
+}
+
+func (m *recordingMovie) Close() error {
+	m.events = append(m.events, "close")
+	return nil
+}
+
+func TestRendererKeepsOrder(t *testing.T) {
+	w := testWorld()
+	r := NewRenderer(8, 2, 2)
+	first, second := &recordingMovie{}, &recordingMovie{}
+	var want []string
+	for step := 0; step < 50; step++ {
+		r.Frame(first, step, w, testColors)
+		want = append(want, fmt.Sprintf("frame %d 20x12", step))
+	}
+	r.Finish(first)
+	r.Frame(second, 0, w, testColors)
+	r.Finish(second)
+
+	require.NoError(t, r.Close())
+	assert.Equal(t, append(want, "close"), first.events)
+	assert.Equal(t, []string{"frame 0 20x12", "close"}, second.events)
+	assert.Contains(t, r.Throughput(), "51 (")
+}
+
+func TestRendererSnapshotsTheWorld(t *testing.T) {
+	w := testWorld()
+	r := NewRenderer(1, 1, 1)
+	movie := &gifMovie{}
+	r.Frame(movie, 0, w, testColors)
+	// changing the world after submitting must not change the frame
+	w.Cells[w.OffsetXY(8, 4)] = 1
+	require.NoError(t, r.flush())
+
+	frame := movie.anim.Image[0]
+	assert.Equal(t, frame.At(9, 4), frame.At(8, 4))
+	require.NoError(t, r.Close())
+}
+
+func TestRendererReportsErrors(t *testing.T) {
+	r := NewRenderer(2, 4, 1)
+	r.Frame(&recordingMovie{fail: true}, 0, testWorld(), testColors)
+	assert.EqualError(t, r.Close(), "disk full")
+}
+
+func TestRendererWithoutWorkers(t *testing.T) {
+	// no workers means one per cpu, not a renderer that never renders
+	r := NewRenderer(0, 1, 1)
+	movie := &recordingMovie{}
+	r.Frame(movie, 0, testWorld(), testColors)
+	r.Finish(movie)
+	require.NoError(t, r.Close())
+	assert.Equal(t, []string{"frame 0 10x6", "close"}, movie.events)
+}
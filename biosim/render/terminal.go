@@ -0,0 +1,94 @@
+package render
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// TerminalView draws a downsampled picture of the world in the terminal, using ANSI colors.
+// Every character shows two blocks of cells on top of each other, using the upper half block
+// character with one color as foreground and the other as background.
+type TerminalView struct {
+	out     io.Writer
+	width   int  // the number of characters a row of the world is squeezed into
+	cleared bool // the screen is cleared before the first frame, later frames are drawn on top
+}
+
+const (
+	ansiClear     = "\x1b[2J"
+	ansiHome      = "\x1b[H"
+	ansiReset     = "\x1b[0m"
+	ansiClearLine = "\x1b[K"
+	upperHalf     = "▀"
+)
+
+func NewTerminalView(out io.Writer, width int) (*TerminalView, error) {
+	if width < 1 {
+		return nil, fmt.Errorf("the terminal view must be at least 1 character wide, got %d", width)
+	}
+	return &TerminalView{out: out, width: width}, nil
+}
+
+// Draw renders the world with the status line above it
+func (v *TerminalView) Draw(w *world.World, colors []color.RGBA, status string) error {
+	buf := bufio.NewWriter(v.out)
+	if !v.cleared {
+		_, _ = buf.WriteString(ansiClear)
+		v.cleared = true
+	}
+	_, _ = buf.WriteString(ansiHome)
+	_, _ = buf.WriteString(status + ansiClearLine + "\n")
+
+	// each character covers a square block of cells, so the picture keeps the proportions of the world
+	block := (w.XSize + v.width - 1) / v.width
+	if block < 1 {
+		block = 1
+	}
+	for y := 0; y < w.YSize; y += 2 * block {
+		for x := 0; x < w.XSize; x += block {
+			top := blockColor(w, colors, x, y, block)
+			bottom := color.RGBA{A: 0}
+			if y+block < w.YSize {
+				bottom = blockColor(w, colors, x, y+block, block)
+			}
+			_, _ = fmt.Fprintf(buf, "\x1b[38;2;%d;%d;%dm", top.R, top.G, top.B)
+			if bottom.A != 0 {
+				_, _ = fmt.Fprintf(buf, "\x1b[48;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B)
+			} else {
+				_, _ = buf.WriteString("\x1b[49m")
+			}
+			_, _ = buf.WriteString(upperHalf)
+		}
+		_, _ = buf.WriteString(ansiReset + "\n")
+	}
+	return buf.Flush()
+}
+
+// blockColor picks the color of a block of cells. Individuals are the most interesting thing to see,
+// so if there is one anywhere in the block, that is what is shown. Then come barriers, and last the ground.
+func blockColor(w *world.World, colors []color.RGBA, x0, y0, block int) color.RGBA {
+	barrier := false
+	for y := y0; y < y0+block && y < w.YSize; y++ {
+		for x := x0; x < x0+block && x < w.XSize; x++ {
+			switch cell := w.Cells[w.OffsetXY(x, y)]; cell {
+			case world.EMPTY:
+			case world.BARRIER:
+				barrier = true
+			default:
+				return colors[world.PeepID(cell)]
+			}
+		}
+	}
+	switch {
+	case barrier:
+		return barrierColor
+	case w.InArea(w.SurvivalArea, x0, y0):
+		return survivalColor
+	default:
+		return color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	}
+}
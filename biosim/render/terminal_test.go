@@ -0,0 +1,47 @@
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+func TestTerminalView(t *testing.T) {
+	w := testWorld()
+	var out bytes.Buffer
+	view, err := NewTerminalView(&out, 5)
+	require.NoError(t, err)
+
+	require.NoError(t, view.Draw(w, testColors, "generation 1"))
+	first := out.String()
+	assert.True(t, strings.HasPrefix(first, ansiClear+ansiHome+"generation 1"))
+	// 10x6 cells in 5 characters per row is 2x2 cells per half character, so 2 rows of 5 characters
+	assert.Equal(t, 10, strings.Count(first, upperHalf))
+	assert.Equal(t, 3, strings.Count(first, "\n"))
+
+	out.Reset()
+	require.NoError(t, view.Draw(w, testColors, "generation 2"))
+	assert.True(t, strings.HasPrefix(out.String(), ansiHome+"generation 2"))
+
+	_, err = NewTerminalView(&out, 0)
+	assert.Error(t, err)
+}
+
+func TestBlockColor(t *testing.T) {
+	w := testWorld()
+	colors := []color.RGBA{{}, {R: 1, A: 0xff}}
+
+	white := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	assert.Equal(t, survivalColor, blockColor(w, colors, 0, 0, 2))
+	assert.Equal(t, barrierColor, blockColor(w, colors, 4, 0, 2))
+	assert.Equal(t, white, blockColor(w, colors, 6, 0, 2))
+
+	// individuals win over barriers
+	w.Cells[w.OffsetXY(4, 1)] = world.PeepCell(1)
+	assert.Equal(t, colors[1], blockColor(w, colors, 4, 0, 2))
+}
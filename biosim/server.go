@@ -0,0 +1,241 @@
+package biosim
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/render"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// genomeCount is a genome and the number of individuals carrying it
+type genomeCount struct {
+	Count  int              `json:"count"`
+	Genome genome.Genome    `json:"genome"`
+	Brain  *brain.NeuralNet `json:"brain"`
+}
+
+// topGenomes returns the n most common genomes among the individuals, the most common first
+func topGenomes(peeps []*world.Individual, n int) []genomeCount {
+	var result []genomeCount
+	seen := map[string]int{}
+	for _, peep := range peeps {
+		key, err := json.Marshal(peep.Genome)
+		if err != nil {
+			panic(err)
+		}
+		if idx, ok := seen[string(key)]; ok {
+			result[idx].Count++
+			continue
+		}
+		seen[string(key)] = len(result)
+		result = append(result, genomeCount{Count: 1, Genome: peep.Genome, Brain: peep.Brain})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+const dashboard = `<!DOCTYPE html>
+<html>
+<head><title>gobiosim</title></head>
+<body>
+<h1>gobiosim</h1>
+<pre id="status"></pre>
+<img id="frame" src="/api/frame.png" style="image-rendering: pixelated">
+<p>
+<button onclick="post('/api/pause')">pause</button>
+<button onclick="post('/api/resume')">resume</button>
+<button onclick="post('/api/checkpoint')">checkpoint</button>
+</p>
+<script>
+function post(url) { fetch(url, {method: 'POST'}).then(update) }
+function update() {
+	fetch('/api/status').then(r => r.text()).then(t => document.getElementById('status').textContent = t)
+	document.getElementById('frame').src = '/api/frame.png?' + Date.now()
+}
+setInterval(update, 1000)
+update()
+</script>
+</body>
+</html>
+`
+
+// Handler serves a small dashboard, and a json api to look at and control the running simulation.
+// The dashboard shows the first island
+func (s *Simulation) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		_, _ = w.Write([]byte(dashboard))
+	})
+	mux.HandleFunc("/api/status", get(s.serveStatus))
+	mux.HandleFunc("/api/stats", get(s.serveStats))
+	mux.HandleFunc("/api/frame.png", get(s.serveFrame))
+	mux.HandleFunc("/api/genomes", get(s.serveGenomes))
+	mux.HandleFunc("/api/brain.dot", get(s.serveBrain))
+	mux.HandleFunc("/api/pause", post(s.servePause))
+	mux.HandleFunc("/api/resume", post(s.serveResume))
+	mux.HandleFunc("/api/mutation-rate", post(s.serveMutationRate))
+	mux.HandleFunc("/api/checkpoint", post(s.serveCheckpoint))
+	return mux
+}
+func get(f http.HandlerFunc) http.HandlerFunc {
+	return onlyMethod(http.MethodGet, f)
+}
+
+func post(f http.HandlerFunc) http.HandlerFunc {
+	return onlyMethod(http.MethodPost, f)
+}
+
+func onlyMethod(method string, f http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		f(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// intParam reads an integer from the query string, falling back to the default if it is missing
+func intParam(r *http.Request, name string, def int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func (s *Simulation) serveStatus(w http.ResponseWriter, _ *http.Request) {
+	island := s.islands[0]
+	island.mu.Lock()
+	status := struct {
+		Generation   int   `json:"generation"`
+		Step         int   `json:"step"`
+		Paused       bool  `json:"paused"`
+		Population   int   `json:"population"`
+		MutationRate int64 `json:"mutation_rate"`
+	}{
+		Generation:   island.generation,
+		Step:         island.currentStep,
+		Paused:       island.paused,
+		Population:   len(island.world.Peeps),
+		MutationRate: s.MutationRate(),
+	}
+	island.mu.Unlock()
+	writeJSON(w, status)
+}
+
+// serveStats writes the stats of every island, one list per island
+func (s *Simulation) serveStats(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, s.IslandHistory())
+}
+
+func (s *Simulation) serveFrame(w http.ResponseWriter, _ *http.Request) {
+	island := s.islands[0]
+	island.mu.Lock()
+	img := render.RenderFrame(island.world, island.world.Cells, render.PeepColors(island.world, s.config.Coloring), 1)
+	island.mu.Unlock()
+	w.Header().Set("Content-Type", "image/png")
+	if err := png.Encode(w, img); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (s *Simulation) serveGenomes(w http.ResponseWriter, r *http.Request) {
+	n, err := intParam(r, "n", 10)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	island := s.islands[0]
+	island.mu.Lock()
+	top := topGenomes(island.world.Peeps, n)
+	island.mu.Unlock()
+	writeJSON(w, top)
+}
+
+func (s *Simulation) serveBrain(w http.ResponseWriter, r *http.Request) {
+	rank, err := intParam(r, "rank", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	island := s.islands[0]
+	island.mu.Lock()
+	top := topGenomes(island.world.Peeps, rank+1)
+	island.mu.Unlock()
+	if rank < 0 || rank >= len(top) {
+		http.Error(w, fmt.Sprintf("there is no genome with rank %d", rank), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/vnd.graphviz")
+	_, _ = w.Write([]byte(top[rank].Brain.DOT()))
+}
+
+func (s *Simulation) servePause(w http.ResponseWriter, r *http.Request) {
+	island := s.islands[0]
+	island.mu.Lock()
+	island.paused = true
+	island.mu.Unlock()
+	s.serveStatus(w, r)
+}
+
+func (s *Simulation) serveResume(w http.ResponseWriter, r *http.Request) {
+	island := s.islands[0]
+	island.mu.Lock()
+	island.paused = false
+	island.resumed.Broadcast()
+	island.mu.Unlock()
+	s.serveStatus(w, r)
+}
+
+func (s *Simulation) serveMutationRate(w http.ResponseWriter, r *http.Request) {
+	rate, err := strconv.ParseInt(r.URL.Query().Get("rate"), 10, 64)
+	if err == nil {
+		err = validateMutationRate(rate)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.SetMutationRate(rate)
+	s.serveStatus(w, r)
+}
+
+func (s *Simulation) serveCheckpoint(w http.ResponseWriter, _ *http.Request) {
+	island := s.islands[0]
+	island.mu.Lock()
+	filename := filepath.Join(s.config.CheckpointDir, fmt.Sprintf("checkpoint-%04d.json", island.generation))
+	err := writeCheckpoint(filename, island.generation, island.world.Peeps)
+	island.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, map[string]string{"file": filename})
+}
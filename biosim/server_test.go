@@ -0,0 +1,132 @@
+package biosim
+
+import (
+	"encoding/json"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+func testSimulation(t *testing.T) *Simulation {
+	config := DefaultConfig()
+	config.CheckpointDir = t.TempDir()
+	w := world.NewScenarioWorld(config.Config, &world.Scenario{Size: world.Coord{X: 10, Y: 6}, StepsPerGeneration: 10})
+	peep := world.CreateIndividual(w, rnd)
+	w.AddPeep(peep)
+	twin := *peep
+	twin.Location = w.RandomCoord(rnd)
+	twin.BirthPlace = twin.Location
+	w.AddPeep(&twin)
+	w.AddPeep(world.CreateIndividual(w, rnd))
+
+	i := newIsland(w, config, 1)
+	i.generation = 3
+	i.currentStep = 7
+	i.history = []GenerationStats{{Generation: 2, Population: 3, Survivors: 1}}
+	return newSimulation([]*island{i}, config)
+}
+
+func request(t *testing.T, s *Simulation, method, url string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, httptest.NewRequest(method, url, nil))
+	return w
+}
+
+func TestServerStatus(t *testing.T) {
+	s := testSimulation(t)
+	w := request(t, s, http.MethodGet, "/api/status")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"generation": 3, "step": 7, "paused": false, "population": 3, "mutation_rate": 100}`, w.Body.String())
+
+	w = request(t, s, http.MethodPost, "/api/status")
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestServerStats(t *testing.T) {
+	w := request(t, testSimulation(t), http.MethodGet, "/api/stats")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[[{"generation": 2, "population": 3, "survivors": 1, "budget_exhausted": 0, "expressed_ratio": 0, "diversity": 0, "immigrants": 0, "migrants": 0, "mutation_rate": 0, "mutation_bias": 0}]]`, w.Body.String())
+}
+
+func TestServerFrame(t *testing.T) {
+	w := request(t, testSimulation(t), http.MethodGet, "/api/frame.png")
+	require.Equal(t, http.StatusOK, w.Code)
+	img, err := png.Decode(w.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 10, img.Bounds().Dx())
+}
+
+func TestServerGenomes(t *testing.T) {
+	s := testSimulation(t)
+	w := request(t, s, http.MethodGet, "/api/genomes?n=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	var top []struct {
+		Count int `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &top))
+	assert.Equal(t, 1, len(top))
+	assert.Equal(t, 2, top[0].Count)
+
+	w = request(t, s, http.MethodGet, "/api/brain.dot?rank=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "digraph brain {"))
+
+	w = request(t, s, http.MethodGet, "/api/brain.dot?rank=2")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestServerPauseAndResume(t *testing.T) {
+	s := testSimulation(t)
+	w := request(t, s, http.MethodPost, "/api/pause")
+	require.Equal(t, http.StatusOK, w.Code)
+	island := s.islands[0]
+	assert.True(t, island.paused)
+
+	done := make(chan struct{})
+	go func() {
+		island.mu.Lock()
+		for island.paused {
+			island.resumed.Wait()
+		}
+		island.mu.Unlock()
+		close(done)
+	}()
+
+	request(t, s, http.MethodPost, "/api/resume")
+	<-done
+	assert.False(t, island.paused)
+}
+
+func TestServerMutationRate(t *testing.T) {
+	s := testSimulation(t)
+
+	w := request(t, s, http.MethodPost, "/api/mutation-rate?rate=42")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.EqualValues(t, 42, s.MutationRate())
+
+	for _, rate := range []string{"0", "1001", "many"} {
+		w = request(t, s, http.MethodPost, "/api/mutation-rate?rate="+rate)
+		assert.Equal(t, http.StatusBadRequest, w.Code, rate)
+		assert.EqualValues(t, 42, s.MutationRate())
+	}
+}
+
+func TestServerCheckpoint(t *testing.T) {
+	s := testSimulation(t)
+	w := request(t, s, http.MethodPost, "/api/checkpoint")
+	require.Equal(t, http.StatusOK, w.Code)
+	var response map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+
+	cp, err := readCheckpoint(response["file"])
+	require.NoError(t, err)
+	assert.Equal(t, 3, cp.Generation)
+	require.Len(t, cp.Genomes, 3)
+	assert.Equal(t, s.World().Peeps[2].Genome, cp.Genomes[2])
+}
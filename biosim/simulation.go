@@ -0,0 +1,394 @@
+// Package biosim evolves populations of individuals with neural net brains, that live in worlds where
+// only some of them survive to breed the next generation. Simulation is the place to start.
+//
+// Simulations have nothing in common, so a process can run as many of them as it likes, at the same time.
+// Each draws its random numbers from the seed of its config, and a config with the same seed makes the same run
+package biosim
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+type (
+	// Simulation runs populations of individuals through generations of living and selection. Every island
+	// has a world and a population of its own, and every now and then some survivors migrate to another island,
+	// to breed there
+	Simulation struct {
+		islands []*island
+		config  Config
+
+		generation int
+		// history holds the stats of all islands together, one entry per generation
+		history []GenerationStats
+		adapter rateAdapter
+		// mutationRate is the chance of a mutation, x in 1000. It can be changed while the simulation is running,
+		// so only touch it through sync/atomic
+		mutationRate int64
+
+		genePool []genome.Genome
+		lineage  *lineageLog
+		workers  *workerPool
+
+		// rnd seeds the islands, and picks the migrants and where they go. The islands draw their other
+		// random numbers from sources of their own
+		rnd *rand.Rand
+
+		onStep       []func(step int)
+		onGeneration []func(stats GenerationStats, survivors []*world.Individual)
+	}
+
+	// Option changes the way a simulation is set up, see New
+	Option func(s *Simulation)
+
+	// Topology decides which islands migrants can go to
+	Topology uint8
+)
+
+const (
+	// TopologyRing sends migrants to the next island, and from the last island to the first
+	TopologyRing Topology = iota
+
+	// TopologyFull sends every migrant to a randomly picked other island
+	TopologyFull
+)
+
+const (
+	MIGRANTS      = 5
+	MIGRATE_EVERY = 10
+)
+
+var topologyNames = map[Topology]string{
+	TopologyRing: "ring",
+	TopologyFull: "full",
+}
+
+func (t Topology) String() string {
+	return topologyNames[t]
+}
+
+// Set implements flag.Value
+func (t *Topology) Set(s string) error {
+	for topology, name := range topologyNames {
+		if name == s {
+			*t = topology
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown topology %q", s)
+}
+
+// OnStep calls the hook after every step, with the step of the generation that was just made. The hook runs
+// in between steps, so it can look at the worlds without locking
+func OnStep(hook func(step int)) Option {
+	return func(s *Simulation) {
+		s.onStep = append(s.onStep, hook)
+	}
+}
+
+// OnGeneration calls the hook at the end of every generation, with its stats and its survivors, once the next
+// generation has been born
+func OnGeneration(hook func(stats GenerationStats, survivors []*world.Individual)) Option {
+	return func(s *Simulation) {
+		s.onGeneration = append(s.onGeneration, hook)
+	}
+}
+
+// WithGenePool gives the immigrants of the pool immigration policy genomes from the pool, instead of from the
+// gene pool file of the config
+func WithGenePool(pool []genome.Genome) Option {
+	return func(s *Simulation) {
+		s.genePool = pool
+	}
+}
+
+// New sets up a simulation with a random population on every island, laid out like the scenarios of the config
+// say. A seed of 0 in the config is replaced with one from the clock
+func New(config Config, options ...Option) (*Simulation, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	s := newSimulation(nil, config)
+	for _, option := range options {
+		option(s)
+	}
+
+	if config.Immigration == ImmigrationPool && s.genePool == nil {
+		if config.GenePool == "" {
+			return nil, fmt.Errorf("pool immigration needs a gene pool")
+		}
+		pool, err := loadGenePool(config.GenePool, config.GenomeBounds)
+		if err != nil {
+			return nil, err
+		}
+		s.genePool = pool
+	}
+	if config.LineageLog != "" {
+		lineage, err := createLineageLog(config.LineageLog)
+		if err != nil {
+			return nil, err
+		}
+		s.lineage = lineage
+	}
+
+	s.workers = newWorkerPool(config.Workers)
+	// the islands share their ids, so migrants don't run into individuals of the island they go to
+	ids := &world.IDs{}
+	for i := 0; i < config.Islands; i++ {
+		w := world.NewScenarioWorld(config.Config, config.Scenarios[i%len(config.Scenarios)])
+		w.IDs = ids
+		island := newIsland(w, config, s.rnd.Int63())
+		world.FillWithRandomPeeps(w, island.rnd)
+		island.genePool = s.genePool
+		island.lineage = s.lineage
+		island.workers = s.workers
+		for _, peep := range w.Peeps {
+			s.lineage.birth(peep)
+		}
+		s.islands = append(s.islands, island)
+	}
+	return s, nil
+}
+
+func newSimulation(islands []*island, config Config) *Simulation {
+	return &Simulation{
+		islands:      islands,
+		config:       config,
+		mutationRate: config.MutationRate,
+		rnd:          rand.New(rand.NewSource(config.Seed)),
+	}
+}
+
+// Step makes the next step of the current generation on every island, in parallel. It returns false, without
+// doing anything, when there are no steps of the generation left, and it is time for RunGeneration to end it
+func (s *Simulation) Step() bool {
+	stepped := make([]bool, len(s.islands))
+	if len(s.islands) == 1 {
+		stepped[0] = s.islands[0].step()
+	} else {
+		var wg sync.WaitGroup
+		for idx := range s.islands {
+			wg.Add(1)
+			go func(idx int) {
+				defer wg.Done()
+				stepped[idx] = s.islands[idx].step()
+			}(idx)
+		}
+		wg.Wait()
+	}
+
+	// the hooks follow the steps of the first island
+	if stepped[0] {
+		for _, hook := range s.onStep {
+			hook(s.islands[0].currentStep - 1)
+		}
+	}
+	for _, ok := range stepped {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RunGeneration makes the steps left of the current generation, and ends it: every island is culled, migrants
+// move, and the survivors breed the next generation. It returns the stats of all islands together
+func (s *Simulation) RunGeneration() GenerationStats {
+	for s.Step() {
+	}
+	survivors, stats := s.selectAndBreed()
+	for _, hook := range s.onGeneration {
+		hook(stats, survivors)
+	}
+	return stats
+}
+
+// selectAndBreed culls every island, lets migrants move, and fills the islands with the next generation.
+// It returns the survivors of all islands, and the stats of all islands together
+func (s *Simulation) selectAndBreed() ([]*world.Individual, GenerationStats) {
+	rate := s.MutationRate()
+	survivors := make([][]*world.Individual, len(s.islands))
+	stats := make([]GenerationStats, len(s.islands))
+	for idx, island := range s.islands {
+		island.mu.Lock()
+		survivors[idx], stats[idx] = island.endGeneration(rate)
+	}
+
+	if len(s.islands) > 1 && s.config.MigrateEvery > 0 && s.generation%s.config.MigrateEvery == 0 {
+		for idx, arrived := range s.migrate(survivors) {
+			stats[idx].Migrants = arrived
+		}
+	}
+
+	var all []*world.Individual
+	for idx, island := range s.islands {
+		all = append(all, survivors[idx]...)
+		island.history = append(island.history, stats[idx])
+	}
+	s.lineage.cull(s.generation, all)
+
+	total := combineStats(stats)
+	s.history = append(s.history, total)
+	if s.config.MutationSchedule == MutationAdaptive {
+		s.SetMutationRate(s.adapter.adapt(rate, total.Survivors, total.Diversity))
+	}
+
+	for idx, island := range s.islands {
+		// an island that died out stays empty, and no one immigrates to it
+		island.immigrants = 0
+		if len(survivors[idx]) > 0 {
+			island.immigrants = island.reproduce(survivors[idx], rate)
+		}
+		island.nextGeneration()
+		island.mu.Unlock()
+	}
+	s.generation++
+	return all, total
+}
+
+// migrate moves up to config.Migrants randomly picked survivors away from every island. Which island they
+// go to depends on the topology. It returns the number of migrants that arrived on every island
+func (s *Simulation) migrate(survivors [][]*world.Individual) []int {
+	leaving := make([][]*world.Individual, len(survivors))
+	for idx, peeps := range survivors {
+		s.rnd.Shuffle(len(peeps), func(i, j int) {
+			peeps[i], peeps[j] = peeps[j], peeps[i]
+		})
+		n := min(s.config.Migrants, len(peeps))
+		leaving[idx] = peeps[:n:n]
+		survivors[idx] = peeps[n:]
+	}
+
+	arrived := make([]int, len(survivors))
+	for from, migrants := range leaving {
+		for _, migrant := range migrants {
+			to := (from + 1) % len(survivors)
+			if s.config.Topology == TopologyFull {
+				to = (from + 1 + s.rnd.Intn(len(survivors)-1)) % len(survivors)
+			}
+			survivors[to] = append(survivors[to], migrant)
+			arrived[to]++
+		}
+	}
+	return arrived
+}
+
+// World returns the world of the first island. Outside of the hooks, the world can change while you look at it
+func (s *Simulation) World() *world.World {
+	return s.islands[0].world
+}
+
+// Worlds returns the worlds of all islands
+func (s *Simulation) Worlds() []*world.World {
+	worlds := make([]*world.World, 0, len(s.islands))
+	for _, island := range s.islands {
+		worlds = append(worlds, island.world)
+	}
+	return worlds
+}
+
+// Generation returns the generation that is currently living
+func (s *Simulation) Generation() int {
+	return s.generation
+}
+
+// History returns the stats of all generations that have ended, the oldest first
+func (s *Simulation) History() []GenerationStats {
+	return s.history
+}
+
+// IslandHistory returns the stats of every island, one list per island, each with the oldest generation first.
+// It can be called while the simulation is running
+func (s *Simulation) IslandHistory() [][]GenerationStats {
+	histories := make([][]GenerationStats, 0, len(s.islands))
+	for _, island := range s.islands {
+		island.mu.Lock()
+		histories = append(histories, append([]GenerationStats(nil), island.history...))
+		island.mu.Unlock()
+	}
+	return histories
+}
+
+// MutationRate returns the chance of a mutation, x in 1000
+func (s *Simulation) MutationRate() int64 {
+	return atomic.LoadInt64(&s.mutationRate)
+}
+
+// SetMutationRate changes the chance of a mutation, x in 1000. It can be called while the simulation is running
+func (s *Simulation) SetMutationRate(rate int64) {
+	atomic.StoreInt64(&s.mutationRate, rate)
+}
+
+// SurvivorsPerIsland describes how many survivors every island had in the last generation
+func (s *Simulation) SurvivorsPerIsland() string {
+	var parts []string
+	for _, island := range s.islands {
+		island.mu.Lock()
+		if len(island.history) > 0 {
+			parts = append(parts, fmt.Sprint(island.history[len(island.history)-1].Survivors))
+		}
+		island.mu.Unlock()
+	}
+	return strings.Join(parts, " ")
+}
+
+// Close stops the workers, and closes the lineage log. The simulation can't be used after this
+func (s *Simulation) Close() error {
+	if s.workers != nil {
+		s.workers.close()
+	}
+	return s.lineage.close()
+}
+
+// combineStats adds up the stats of several islands for the same generation.
+// Ratios are averaged, weighted by the population of the islands
+func combineStats(stats []GenerationStats) GenerationStats {
+	total := GenerationStats{
+		Mutations:          map[string]int{},
+		SurvivingMutations: map[string]int{},
+	}
+	for _, s := range stats {
+		total.Generation = s.Generation
+		total.MutationRate = s.MutationRate
+		total.Population += s.Population
+		total.Survivors += s.Survivors
+		total.BudgetExhausted += s.BudgetExhausted
+		total.Immigrants += s.Immigrants
+		total.Migrants += s.Migrants
+		total.Clusters += s.Clusters
+		total.ClusterSizes = append(total.ClusterSizes, s.ClusterSizes...)
+		total.ExpressedRatio += s.ExpressedRatio * float64(s.Population)
+		total.Diversity += s.Diversity * float64(s.Population)
+		total.MutationBias += s.MutationBias * float64(s.Population)
+		for name, n := range s.Mutations {
+			total.Mutations[name] += n
+		}
+		for name, n := range s.SurvivingMutations {
+			total.SurvivingMutations[name] += n
+		}
+		for name, n := range s.Species {
+			if total.Species == nil {
+				total.Species = map[string]int{}
+			}
+			total.Species[name] += n
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(total.ClusterSizes)))
+	if total.Population > 0 {
+		total.ExpressedRatio /= float64(total.Population)
+		total.Diversity /= float64(total.Population)
+		total.MutationBias /= float64(total.Population)
+	}
+	return total
+}
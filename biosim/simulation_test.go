@@ -0,0 +1,268 @@
+package biosim
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// rnd gives the tests their random numbers
+var rnd = rand.New(rand.NewSource(1))
+
+func peepsWithIDs(ids ...int) []*world.Individual {
+	var peeps []*world.Individual
+	for _, id := range ids {
+		peeps = append(peeps, &world.Individual{ID: id})
+	}
+	return peeps
+}
+
+// smallConfig is a quick simulation, with a small population in a small world
+func smallConfig() Config {
+	config := DefaultConfig()
+	config.Species = world.SpeciesList{{Name: "peeps", Population: 50, Selection: world.SelectArea}}
+	config.Scenarios = world.Scenarios{{
+		Size:               world.Coord{X: 30, Y: 30},
+		StepsPerGeneration: 5,
+		SurvivalArea:       world.Area{BottomRight: world.Coord{X: 15, Y: 30}},
+	}}
+	config.Workers = 2
+	return config
+}
+
+func TestNew(t *testing.T) {
+	s, err := New(smallConfig())
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Len(t, s.Worlds(), 1)
+	assert.Len(t, s.World().Peeps, 50)
+	assert.EqualValues(t, genome.MUTATION_RATE, s.MutationRate())
+
+	config := smallConfig()
+	config.Islands = 0
+	_, err = New(config)
+	assert.Error(t, err)
+
+	config = smallConfig()
+	// barriers fill all but 50 cells of the world, which is too little room for 50 peeps
+	config.Scenarios[0].Barriers = []world.Area{{BottomRight: world.Coord{X: 30, Y: 28}}, {TopLeft: world.Coord{Y: 28}, BottomRight: world.Coord{X: 5, Y: 30}}}
+	_, err = New(config)
+	assert.Error(t, err)
+
+	config = smallConfig()
+	config.Scenarios[0].Events = []world.Event{{Step: 5, RotateSurvivalArea: true}}
+	_, err = New(config)
+	assert.Error(t, err, "an event after the last step")
+
+	config = smallConfig()
+	config.Immigration = ImmigrationPool
+	_, err = New(config)
+	assert.Error(t, err, "pool immigration without a pool")
+}
+
+func TestStepAndRunGeneration(t *testing.T) {
+	var steps []int
+	var generations []GenerationStats
+	config := smallConfig()
+	config.Islands = 2
+	s, err := New(config,
+		OnStep(func(step int) { steps = append(steps, step) }),
+		OnGeneration(func(stats GenerationStats, survivors []*world.Individual) {
+			assert.Equal(t, stats.Survivors, len(survivors))
+			generations = append(generations, stats)
+		}))
+	require.NoError(t, err)
+	defer s.Close()
+
+	assert.True(t, s.Step())
+	assert.Equal(t, []int{0}, steps)
+
+	stats := s.RunGeneration()
+	assert.Equal(t, []int{0, 1, 2, 3, 4}, steps)
+	require.Len(t, generations, 1)
+	assert.Equal(t, stats, generations[0])
+	assert.Equal(t, 100, stats.Population)
+	assert.Equal(t, 1, s.Generation())
+	assert.Equal(t, []GenerationStats{stats}, s.History())
+	for _, w := range s.Worlds() {
+		assert.Len(t, w.Peeps, 50)
+	}
+}
+
+func TestSameSeedSameRun(t *testing.T) {
+	run := func(workers int, change func(*Config)) []GenerationStats {
+		config := smallConfig()
+		config.Seed = 42
+		// wide enough for several strips, that are moved on several workers at the same time
+		config.Scenarios[0].Size = world.Coord{X: 100, Y: 30}
+		config.Species[0].Population = 500
+		config.Workers = workers
+		change(&config)
+		s, err := New(config)
+		require.NoError(t, err)
+		defer s.Close()
+		for generation := 0; generation < 3; generation++ {
+			s.RunGeneration()
+		}
+		return s.History()
+	}
+
+	for name, change := range map[string]func(*Config){
+		"path":          func(c *Config) { c.Movement = world.MovementPath },
+		"jump":          func(c *Config) { c.Movement = world.MovementJump },
+		"probabilistic": func(c *Config) { c.Movement = world.MovementProbabilistic },
+		"shared":        func(c *Config) { c.Reproduction = ReproduceShared },
+	} {
+		first := run(8, change)
+		assert.Equal(t, first, run(8, change), name)
+		assert.Equal(t, first, run(1, change), "the number of workers doesn't change the outcome of %s", name)
+	}
+}
+
+func TestSelectAndBreed_DiedOut(t *testing.T) {
+	config := DefaultConfig()
+	config.Immigration = ImmigrationRandom
+	config.MigrateEvery = 0
+	var islands []*island
+	for i := 0; i < 2; i++ {
+		w := &world.World{XSize: 50, YSize: 50, Cells: make([]world.Cell, 2500), Config: config.Config}
+		w.SurvivalArea = world.Area{BottomRight: world.Coord{X: 50, Y: 50}}
+		islands = append(islands, newIsland(w, config, int64(i)))
+	}
+	islands[0].world.AddPeep(world.CreateIndividual(islands[0].world, rnd))
+	// the second island is empty, but its last generation had immigrants
+	islands[1].immigrants = 7
+	a := newSimulation(islands, config)
+
+	a.selectAndBreed()
+	a.selectAndBreed()
+	history := a.IslandHistory()
+	require.Len(t, history, 2)
+	require.Len(t, history[1], 2)
+	assert.Equal(t, 7, history[1][0].Immigrants)
+	assert.Equal(t, 0, history[1][1].Immigrants, "no one immigrates to an island that died out")
+	assert.Equal(t, 0, history[1][1].Population)
+	assert.Positive(t, history[0][1].Immigrants)
+}
+
+func TestSimulationsSideBySide(t *testing.T) {
+	config := smallConfig()
+	config.Islands = 3
+	config.Topology = TopologyFull
+	config.MigrateEvery = 1
+	config.Reproduction = ReproduceShared
+	config.Seed = 7
+
+	type result struct {
+		history []GenerationStats
+		genomes [][]genome.Genome
+	}
+	results := make([]result, 2)
+	var wg sync.WaitGroup
+	for idx := range results {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			s, err := New(config)
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer s.Close()
+			for generation := 0; generation < 4; generation++ {
+				s.RunGeneration()
+			}
+			results[idx].history = s.History()
+			for _, w := range s.Worlds() {
+				var genomes []genome.Genome
+				for _, peep := range w.Peeps {
+					genomes = append(genomes, peep.Genome)
+				}
+				results[idx].genomes = append(results[idx].genomes, genomes)
+			}
+		}(idx)
+	}
+	wg.Wait()
+	require.Len(t, results[0].history, 4)
+	assert.Equal(t, results[0], results[1], "simulations with the same seed don't get in each other's way")
+}
+
+func TestWithGenePool(t *testing.T) {
+	g := genome.MakeRandomGenome(5, rnd)
+	config := smallConfig()
+	config.Immigration = ImmigrationPool
+	s, err := New(config, WithGenePool([]genome.Genome{g}))
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, []genome.Genome{g}, s.islands[0].genePool)
+}
+
+func TestMigrate_Ring(t *testing.T) {
+	config := DefaultConfig()
+	config.Migrants = 2
+	a := newSimulation(nil, config)
+	survivors := [][]*world.Individual{peepsWithIDs(1, 2, 3), peepsWithIDs(4), nil}
+
+	arrived := a.migrate(survivors)
+	assert.Equal(t, []int{0, 2, 1}, arrived)
+	assert.Len(t, survivors[0], 1)
+	assert.Len(t, survivors[1], 2)
+	assert.Equal(t, 4, survivors[2][0].ID)
+}
+
+func TestMigrate_Full(t *testing.T) {
+	config := DefaultConfig()
+	config.Migrants = 10
+	config.Topology = TopologyFull
+	a := newSimulation(nil, config)
+	survivors := [][]*world.Individual{peepsWithIDs(1, 2, 3), nil, nil}
+
+	arrived := a.migrate(survivors)
+	assert.Empty(t, survivors[0], "nobody can migrate to where they came from")
+	assert.Equal(t, 3, arrived[1]+arrived[2])
+}
+
+func TestCombineStats(t *testing.T) {
+	total := combineStats([]GenerationStats{
+		{Generation: 4, Population: 100, Survivors: 10, Diversity: 1, Mutations: map[string]int{"weight": 2}},
+		{Generation: 4, Population: 300, Survivors: 20, Diversity: 0.5, Mutations: map[string]int{"weight": 1, "swap": 1}},
+	})
+	assert.Equal(t, 4, total.Generation)
+	assert.Equal(t, 400, total.Population)
+	assert.Equal(t, 30, total.Survivors)
+	assert.InDelta(t, 0.625, total.Diversity, 0.0001)
+	assert.Equal(t, map[string]int{"weight": 3, "swap": 1}, total.Mutations)
+}
+
+func TestSelectAndBreed(t *testing.T) {
+	config := DefaultConfig()
+	config.Immigration = ImmigrationNone
+	config.MigrateEvery = 1
+	var islands []*island
+	// the islands of a simulation share their ids, so the migrants can be told apart
+	ids := &world.IDs{}
+	for i := 0; i < 2; i++ {
+		w := &world.World{XSize: 50, YSize: 50, Cells: make([]world.Cell, 2500), Config: config.Config, IDs: ids}
+		w.SurvivalArea = world.Area{BottomRight: world.Coord{X: 50, Y: 50}}
+		w.AddPeep(world.CreateIndividual(w, rnd))
+		islands = append(islands, newIsland(w, config, int64(i)))
+	}
+	a := newSimulation(islands, config)
+	natives := []int{islands[0].world.Peeps[0].ID, islands[1].world.Peeps[0].ID}
+
+	survivors, stats := a.selectAndBreed()
+	assert.Len(t, survivors, 2)
+	assert.Equal(t, 2, stats.Survivors)
+	assert.Equal(t, 2, stats.Migrants)
+	require.Len(t, a.history, 1)
+	for idx, island := range islands {
+		assert.Len(t, island.world.Peeps, world.POPULATION)
+		// with a ring of two, the survivors trade places, so the offspring of each island come from the other one
+		assert.Equal(t, natives[1-idx], island.world.Peeps[0].Parents[0])
+		assert.Equal(t, 1, island.history[0].Migrants)
+	}
+}
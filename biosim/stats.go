@@ -0,0 +1,88 @@
+package biosim
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+// GenerationStats is what we know about a generation once it has been culled
+type GenerationStats struct {
+	Generation int `json:"generation"`
+	Population int `json:"population"`
+	Survivors  int `json:"survivors"`
+
+	// BudgetExhausted is the number of times an individual ran out of signal budget during the generation
+	BudgetExhausted int64 `json:"budget_exhausted"`
+
+	// ExpressedRatio is the share of genes that made it into a brain
+	ExpressedRatio float64 `json:"expressed_ratio"`
+	Diversity      float64 `json:"diversity"`
+	Immigrants     int     `json:"immigrants"`
+	Migrants       int     `json:"migrants"`
+
+	// Species counts the survivors of every species, when there is more than one
+	Species map[string]int `json:"species,omitempty"`
+
+	// Clusters is the number of clusters of similar genomes with shared reproduction, and ClusterSizes
+	// their sizes, largest first
+	Clusters     int   `json:"clusters,omitempty"`
+	ClusterSizes []int `json:"cluster_sizes,omitempty"`
+
+	// MutationRate is the global mutation rate the generation was born with, and MutationBias the average
+	// mutation bias of its genomes
+	MutationRate int64   `json:"mutation_rate"`
+	MutationBias float64 `json:"mutation_bias"`
+
+	// Mutations counts the mutations the population was born with, by operator,
+	// and SurvivingMutations counts the same for the survivors
+	Mutations          map[string]int `json:"mutations,omitempty"`
+	SurvivingMutations map[string]int `json:"surviving_mutations,omitempty"`
+}
+
+// MutationSummary tells, for every mutation operator, how many mutations it made over all generations,
+// and how many of those were carried by survivors. An individual with two mutations of the same kind
+// counts twice
+func MutationSummary(history []GenerationStats) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-10s %10s %10s %9s\n", "mutation", "mutations", "survived", "survival")
+	for _, op := range genome.MutationOperators {
+		var mutations, survived int
+		for _, stats := range history {
+			mutations += stats.Mutations[op.Name()]
+			survived += stats.SurvivingMutations[op.Name()]
+		}
+		if mutations == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "%-10s %10d %10d %8.1f%%\n", op.Name(), mutations, survived, 100*float64(survived)/float64(mutations))
+	}
+	return sb.String()
+}
+
+// countMutations counts the mutations the individuals carry, by operator
+func countMutations(peeps []*world.Individual) map[string]int {
+	result := map[string]int{}
+	for _, peep := range peeps {
+		for _, mutation := range peep.Mutations {
+			result[genome.MutationOperatorName(mutation)]++
+		}
+	}
+	return result
+}
+
+// WriteStats writes the stats of every generation to the file as JSON. An empty filename writes nothing
+func WriteStats(filename string, history []GenerationStats) error {
+	if filename == "" {
+		return nil
+	}
+	data, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, data, 0o644)
+}
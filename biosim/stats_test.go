@@ -0,0 +1,28 @@
+package biosim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+func TestCountMutations(t *testing.T) {
+	peeps := []*world.Individual{
+		{Mutations: []string{"weight 1", "insert 0"}},
+		{Mutations: []string{"weight 3", "flip 2 sink"}},
+		{},
+	}
+	assert.Equal(t, map[string]int{"weight": 2, "insert": 1, "flip": 1}, countMutations(peeps))
+}
+
+func TestMutationSummary(t *testing.T) {
+	history := []GenerationStats{
+		{Mutations: map[string]int{"weight": 3}, SurvivingMutations: map[string]int{"weight": 1}},
+		{Mutations: map[string]int{"weight": 1, "insert": 2}},
+	}
+	assert.Equal(t, `mutation    mutations   survived  survival
+weight              4          1     25.0%
+insert              2          0      0.0%
+`, MutationSummary(history))
+}
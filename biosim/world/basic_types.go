@@ -1,4 +1,4 @@
-package main
+package world
 
 type (
 	// Compass - an enum with enumerants N=0, NE, E, SW, S, SW, W, NW, CENTER
@@ -6,7 +6,8 @@ type (
 
 	// Coord - signed int16 pair, absolute location or difference of locations
 	Coord struct {
-		X, Y int
+		X int `json:"x"`
+		Y int `json:"y"`
 	}
 
 	Polar struct {
@@ -15,7 +16,8 @@ type (
 	}
 
 	Area struct {
-		TopLeft, BottomRight Coord
+		TopLeft     Coord `json:"top_left"`
+		BottomRight Coord `json:"bottom_right"`
 	}
 )
 
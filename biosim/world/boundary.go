@@ -0,0 +1,131 @@
+package world
+
+import "fmt"
+
+// Boundary decides what happens at the edges of the world
+type Boundary uint8
+
+const (
+	// BoundaryClamp makes the edges walls. Moving past them ends up at the edge
+	BoundaryClamp Boundary = iota
+
+	// BoundaryTorus wraps the world around, so moving past one edge comes back in at the opposite edge.
+	// There are no edges, so the BOUNDARY_DIST sensors always see the largest possible distance
+	BoundaryTorus
+
+	// BoundaryReflect bounces moves off the edges, like a ball off a wall
+	BoundaryReflect
+)
+
+var boundaryNames = map[Boundary]string{
+	BoundaryClamp:   "clamp",
+	BoundaryTorus:   "torus",
+	BoundaryReflect: "reflect",
+}
+
+func (b Boundary) String() string {
+	return boundaryNames[b]
+}
+
+// Set implements flag.Value
+func (b *Boundary) Set(s string) error {
+	for boundary, name := range boundaryNames {
+		if name == s {
+			*b = boundary
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown boundary %q", s)
+}
+
+// contain brings a location that is outside the world back inside, the way the boundary says
+func (world *World) contain(location Coord) Coord {
+	switch world.Config.Boundary {
+	case BoundaryTorus:
+		location.X = wrap(location.X, world.XSize)
+		location.Y = wrap(location.Y, world.YSize)
+	case BoundaryReflect:
+		location.X = reflect(location.X, world.XSize-1)
+		location.Y = reflect(location.Y, world.YSize-1)
+	}
+	// a move longer than the world is wide can bounce past the other edge
+	location.X = limit(location.X, world.XSize-1)
+	location.Y = limit(location.Y, world.YSize-1)
+	return location
+}
+
+// lookAt returns the cell to look at for the location, and false if the location is outside the world.
+// On a torus nothing is outside the world, and sensors see around the edges
+func (world *World) lookAt(x, y int) (int, int, bool) {
+	if world.Config.Boundary == BoundaryTorus {
+		return wrap(x, world.XSize), wrap(y, world.YSize), true
+	}
+	return x, y, x >= 0 && y >= 0 && x < world.XSize && y < world.YSize
+}
+
+// direction returns the sign of the step it takes to go from one location to another. On a torus it
+// goes the short way, so a move over the edge points the same way as the move itself
+func (world *World) direction(from, to Coord) Coord {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	if world.Config.Boundary == BoundaryTorus {
+		if 2*abs(dx) > world.XSize {
+			dx = -dx
+		}
+		if 2*abs(dy) > world.YSize {
+			dy = -dy
+		}
+	}
+	return Coord{X: sign(dx), Y: sign(dy)}
+}
+
+// InArea tells if the location is inside the area. On a torus, areas reaching past an edge
+// continue at the opposite edge
+func (world *World) InArea(area Area, x, y int) bool {
+	if world.Config.Boundary != BoundaryTorus {
+		return area.inside(x, y)
+	}
+	for _, dx := range []int{-world.XSize, 0, world.XSize} {
+		for _, dy := range []int{-world.YSize, 0, world.YSize} {
+			if area.inside(x+dx, y+dy) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func wrap(v, size int) int {
+	v %= size
+	if v < 0 {
+		v += size
+	}
+	return v
+}
+
+func reflect(v, max int) int {
+	if v < 0 {
+		return -v
+	}
+	if v > max {
+		return 2*max - v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	default:
+		return 0
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
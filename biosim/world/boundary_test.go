@@ -0,0 +1,63 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/brain"
+)
+
+func boundaryWorld(boundary Boundary) *World {
+	config := DefaultConfig()
+	config.Boundary = boundary
+	return &World{XSize: 10, YSize: 10, Cells: make([]Cell, 100), Config: config, StepsPerGeneration: 10}
+}
+
+func TestContain(t *testing.T) {
+	tests := []struct {
+		boundary Boundary
+		in, out  Coord
+	}{
+		{BoundaryClamp, Coord{-3, 12}, Coord{0, 9}},
+		{BoundaryTorus, Coord{-3, 12}, Coord{7, 2}},
+		{BoundaryTorus, Coord{25, -10}, Coord{5, 0}},
+		{BoundaryReflect, Coord{-3, 12}, Coord{3, 6}},
+		{BoundaryReflect, Coord{-30, 4}, Coord{9, 4}},
+	}
+	for _, test := range tests {
+		t.Run(test.boundary.String(), func(t *testing.T) {
+			assert.Equal(t, test.out, boundaryWorld(test.boundary).contain(test.in))
+		})
+	}
+}
+
+func TestMoveAcrossTorus(t *testing.T) {
+	world := boundaryWorld(BoundaryTorus)
+	world.AddPeep(&Individual{})
+	peep := &Individual{Location: Coord{9, 5}, BirthPlace: Coord{9, 5}}
+	world.AddPeep(peep)
+
+	assert.False(t, world.updateLocation(1, Coord{11, 5}))
+	assert.Equal(t, Coord{1, 5}, peep.Location)
+	assert.Equal(t, Coord{1, 0}, world.direction(Coord{9, 5}, peep.Location), "moving over the edge keeps pointing right")
+	assert.Equal(t, PeepCell(1), world.Cells[world.OffsetXY(1, 5)])
+}
+
+func TestBoundarySensors(t *testing.T) {
+	peep := &Individual{Location: Coord{0, 4}}
+	assert.Equal(t, 0.0, getSensorValue(peep, boundaryWorld(BoundaryClamp), brain.BOUNDARY_DIST))
+	assert.Equal(t, 1.0, getSensorValue(peep, boundaryWorld(BoundaryTorus), brain.BOUNDARY_DIST))
+}
+
+func TestAreasWrapOnTorus(t *testing.T) {
+	area := Area{TopLeft: Coord{8, 0}, BottomRight: Coord{11, 2}}
+	assert.False(t, boundaryWorld(BoundaryClamp).InArea(area, 0, 1))
+	assert.True(t, boundaryWorld(BoundaryTorus).InArea(area, 0, 1))
+
+	world := boundaryWorld(BoundaryTorus)
+	world.barriers = []Area{area}
+	world.fillBarriers()
+	assert.Equal(t, BARRIER, world.Cells[world.OffsetXY(9, 0)])
+	assert.Equal(t, BARRIER, world.Cells[world.OffsetXY(0, 1)])
+	assert.Equal(t, EMPTY, world.Cells[world.OffsetXY(1, 1)])
+}
@@ -0,0 +1,12 @@
+//go:build !cells32
+// +build !cells32
+
+package world
+
+import "math"
+
+// Cell holds what is in a spot of the world. 16 bits keep big grids small, and leave room for
+// MAX_PEEPS individuals per world. Build with -tags cells32 for larger populations
+type Cell = uint16
+
+const BARRIER Cell = math.MaxUint16
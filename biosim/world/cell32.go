@@ -0,0 +1,12 @@
+//go:build cells32
+// +build cells32
+
+package world
+
+import "math"
+
+// Cell holds what is in a spot of the world. This is the wide version, for populations of more than
+// 65534 individuals per world, at twice the memory per cell
+type Cell = uint32
+
+const BARRIER Cell = math.MaxUint32
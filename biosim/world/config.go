@@ -0,0 +1,61 @@
+package world
+
+import (
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+)
+
+type (
+	// Config holds the settings the individuals of a world live by
+	Config struct {
+		// SignalBudget is the maximum number of neuron firings that are propagated per individual and step.
+		// Firings left over when the budget is spent are dropped, and counted as an exhausted budget
+		SignalBudget int
+
+		// Recurrence decides when signals from a firing neuron reach their sinks
+		Recurrence brain.Recurrence
+
+		// Leak is the fraction of its accumulated value that every neuron loses at the start of each step.
+		// 0 means neurons never forget, 1 means they only remember signals from the current step
+		Leak float64
+
+		// ResetAtBirth makes offspring start with quiet neurons, instead of inheriting the state of the parent
+		ResetAtBirth bool
+
+		// MutationRates holds the rate of every mutation operator, relative to the global mutation rate
+		MutationRates genome.MutationRates
+
+		// GenomeBounds limits the length of genomes and the number of neurons they can have
+		GenomeBounds genome.GenomeBounds
+
+		// InitialGenes is the max length of randomly created genomes
+		InitialGenes int
+
+		// Species are the kinds of individuals living in the world
+		Species SpeciesList
+
+		// Boundary decides what happens at the edges of the world: walls, wrapping around, or bouncing back
+		Boundary Boundary
+
+		// Movement decides how move actions turn into steps: walking a path, jumping, or moving by chance
+		Movement Movement
+
+		// SizePenalty is the chance, per gene, that an individual dies at the end of a generation even though it
+		// made it to the survival area. It makes smaller brains fitter
+		SizePenalty float64
+	}
+)
+
+const SIGNAL_BUDGET = 10
+
+func DefaultConfig() Config {
+	return Config{
+		SignalBudget:  SIGNAL_BUDGET,
+		Recurrence:    brain.RecurrenceBudget,
+		ResetAtBirth:  true,
+		MutationRates: genome.DefaultMutationRates(),
+		GenomeBounds:  genome.DefaultGenomeBounds(),
+		InitialGenes:  genome.INITIAL_GENES,
+		Species:       DefaultSpecies(),
+	}
+}
@@ -0,0 +1,285 @@
+package world
+
+import (
+	"math"
+	"math/rand"
+	"sync/atomic"
+
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+)
+
+type (
+	Individual struct {
+		Genome     genome.Genome
+		Location   Coord
+		BirthPlace Coord
+		Age        uint16
+		wasBlocked bool // will be true if this individual was not able to do an action last step because it was blocked
+		Brain      *brain.NeuralNet
+
+		// ID is unique for every individual created during a run
+		ID int
+
+		// Parents are the ids of the individuals this one was cloned from. Empty for randomly created individuals
+		Parents []int
+
+		// Born is the generation the individual was born into
+		Born int
+
+		// Mutations describes how the genome differs from the parent's genome
+		Mutations []string
+
+		// Lineage is shared by all descendants of the same randomly created individual
+		Lineage int
+
+		// the action the individual did most of during the last step
+		DominantAction brain.Action
+
+		// neurons that fired last step and have not delivered their signal yet. only used with RecurrenceDelayed
+		pending []*brain.Neuron
+
+		// Species is the index of the individual's species in Config.Species
+		Species int
+
+		// Cluster is the id of the cluster of similar genomes the individual was put in, with shared reproduction
+		Cluster int
+
+		// heading is the direction of the last move, with X and Y each -1, 0 or 1. Zero until the first move
+		heading Coord
+	}
+)
+
+// IDs hands out the ids of individuals, lineages and clusters. The worlds of a simulation share one, so
+// migrants keep ids no one else has. It is safe to use from several goroutines
+type IDs struct {
+	individuals int64
+	lineages    int64
+	clusters    int64
+}
+
+// NextIndividual returns the id of a new individual
+func (ids *IDs) NextIndividual() int {
+	return int(atomic.AddInt64(&ids.individuals, 1))
+}
+
+// NextLineage returns the id of a new lineage
+func (ids *IDs) NextLineage() int {
+	return int(atomic.AddInt64(&ids.lineages, 1))
+}
+
+// NextCluster returns the id of a new cluster of similar genomes
+func (ids *IDs) NextCluster() int {
+	return int(atomic.AddInt64(&ids.clusters, 1))
+}
+
+// CreateIndividual places an individual with a random genome somewhere in the world, drawing from rnd
+func CreateIndividual(world *World, rnd *rand.Rand) *Individual {
+	g := world.Config.GenomeBounds.RandomGenome(world.Config.InitialGenes, rnd)
+	brain, err := g.BuildNet()
+	if err == genome.TooSimple {
+		return CreateIndividual(world, rnd)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return NewIndividual(world, g, brain, rnd)
+}
+
+// NewIndividual places an individual without parents, starting a lineage of its own, somewhere in the world
+func NewIndividual(world *World, genome genome.Genome, net *brain.NeuralNet, rnd *rand.Rand) *Individual {
+	place := world.RandomCoord(rnd)
+	return &Individual{
+		ID:             world.ids().NextIndividual(),
+		Lineage:        world.ids().NextLineage(),
+		Genome:         genome,
+		Location:       place,
+		BirthPlace:     place,
+		Age:            0,
+		Brain:          net,
+		DominantAction: brain.NUM_ACTIONS,
+	}
+}
+
+func FillWithRandomPeeps(world *World, rnd *rand.Rand) {
+	for species, s := range world.Config.Species {
+		for i := 0; i < s.Population; i++ {
+			individual := CreateIndividual(world, rnd)
+			if len(individual.Brain.Connections) < min(3, world.Config.GenomeBounds.MaxGenes) {
+				i--
+				continue
+			}
+			individual.Species = species
+			world.AddPeep(individual)
+		}
+	}
+}
+
+// Think runs the neural net of the individual with the given id, and returns its actions
+func (world *World) Think(id int) brain.Actions {
+	peep := world.Peeps[id]
+	peep.wasBlocked = false
+	return peep.step(world)
+}
+
+func (i *Individual) step(world *World) brain.Actions {
+	// First we build the sensor inputs that the brains uses into a slice
+	inputs := make([]float64, 0, len(i.Brain.Sensors))
+	for _, sensor := range i.Brain.Sensors {
+		value := getSensorValue(i, world, sensor)
+		inputs = append(inputs, value)
+	}
+	actions, pending, exhausted := i.Brain.Think(inputs, i.pending, world.Config.Leak, world.Config.SignalBudget, world.Config.Recurrence)
+	i.pending = pending
+	if exhausted {
+		atomic.AddInt64(&world.BudgetExhausted, 1)
+	}
+	i.Age++
+	return actions
+}
+
+// dominantAction returns the action that was taken the most, or NUM_ACTIONS if no action was taken at all
+func dominantAction(actions brain.Actions) brain.Action {
+	result := brain.NUM_ACTIONS
+	strongest := 0.0
+	for act, value := range actions {
+		if math.Abs(value) > strongest {
+			strongest = math.Abs(value)
+			result = brain.Action(act)
+		}
+	}
+	return result
+}
+
+func plusMinusOne(rnd *rand.Rand) int {
+	if rnd.Intn(2) == 0 {
+		return -1
+	}
+	return 1
+}
+
+// Clone creates an offspring of this individual, with the chance of a mutation being mutationRate in 1000.
+// The offspring never shares neuron state with the parent; its brain is either rebuilt from a mutated genome,
+// or copied from the parent's brain. The mutations are drawn from rnd
+func (i *Individual) Clone(world *World, mutationRate int64, rnd *rand.Rand) *Individual {
+	clone := *i
+	clone.ID = world.ids().NextIndividual()
+	clone.Parents = []int{i.ID}
+	clone.Age = 0
+	clone.pending = nil
+	clone.DominantAction = brain.NUM_ACTIONS
+	clone.heading = Coord{}
+	for {
+		g, mutations := i.Genome.Clone(mutationRate, world.Config.MutationRates, world.Config.GenomeBounds, rnd)
+		if len(mutations) == 0 {
+			clone.Genome = g
+			clone.Mutations = nil
+			clone.Brain = i.Brain.Clone()
+			if world.Config.ResetAtBirth {
+				clone.Brain.Reset()
+			}
+			break
+		}
+		net, err := g.BuildNet()
+		if err == genome.TooSimple {
+			// try again with another mutation
+			continue
+		}
+		if err != nil {
+			panic(err)
+		}
+		clone.Genome = g
+		clone.Mutations = mutations
+		clone.Brain = net
+		break
+	}
+	return &clone
+}
+
+// Expression returns how many of the genes are expressed as a connection in the brain, and how many are dormant
+func (i *Individual) Expression() (expressed, dormant int) {
+	expressed = i.Brain.ExpressedGenes
+	return expressed, len(i.Genome.Genes) - expressed
+}
+
+// ExpressionRatio returns the share of all genes in the population that are expressed in the brains
+func ExpressionRatio(peeps []*Individual) float64 {
+	var expressed, total int
+	for _, peep := range peeps {
+		e, d := peep.Expression()
+		expressed += e
+		total += e + d
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(expressed) / float64(total)
+}
+
+// ResetBrain forgets everything that is going on in the individual's brain
+func (i *Individual) ResetBrain() {
+	i.Brain.Reset()
+	i.pending = nil
+}
+
+func getSensorValue(i *Individual, w *World, s brain.Sensor) float64 {
+	switch s {
+	case brain.LOC_X:
+		// map current X location to value between 0.0..1.0
+		return float64(i.Location.X) / float64(w.XSize)
+	case brain.LOC_Y:
+		// map current Y location to value between 0.0..1.0
+		return float64(i.Location.Y) / float64(w.YSize)
+	case brain.BOUNDARY_DIST:
+		// Finds the closest boundary, compares that to the max possible dist
+		// to a boundary from the center, and converts that linearly to the
+		// sensor range 0.0..1.0
+		x := getSensorValue(i, w, brain.BOUNDARY_DIST_X)
+		y := getSensorValue(i, w, brain.BOUNDARY_DIST_Y)
+
+		return math.Min(x, y)
+
+	case brain.BOUNDARY_DIST_X:
+		if w.Config.Boundary == BoundaryTorus {
+			return 1
+		}
+		maxDist := float64(w.XSize / 2)
+		return float64(min(i.Location.X, w.XSize-i.Location.X-1)) / maxDist
+
+	case brain.BOUNDARY_DIST_Y:
+		if w.Config.Boundary == BoundaryTorus {
+			return 1
+		}
+		maxDist := float64(w.YSize / 2)
+		return float64(min(i.Location.Y, w.YSize-i.Location.Y-1)) / maxDist
+
+	case brain.AGE:
+		// sets the age to a normalized value between 0 and 1
+		return float64(i.Age) / float64(w.StepsPerGeneration)
+
+	case brain.BLOCK:
+		if i.wasBlocked {
+			return 1
+		}
+		return 0
+
+	case brain.SPECIES_FWD:
+		return w.speciesForward(i)
+
+	}
+	panic("oh noes")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
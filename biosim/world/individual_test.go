@@ -0,0 +1,87 @@
+package world
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+)
+
+func TestStuff(t *testing.T) {
+	for i:=0;i<100;i++{
+		fmt.Println(plusMinusOne(rand.New(rand.NewSource(int64(i)))))
+	}
+}
+
+// loopingBrain returns a brain with a neuron that excites itself, so it keeps firing once the BLOCK sensor starts it
+func loopingBrain() *brain.NeuralNet {
+	net := brain.NewNeuralNet(1)
+	net.Connect(brain.BLOCK, 0, 1.5)
+	net.Connect(0, 0, 2)
+	net.Connect(0, brain.MOVE_X, 0.5)
+	return net
+}
+
+func TestSignalBudgetExhausted(t *testing.T) {
+	world := &World{StepsPerGeneration: 10, Config: DefaultConfig()}
+	world.Config.SignalBudget = 3
+	peep := &Individual{Brain: loopingBrain(), wasBlocked: true}
+
+	actions := peep.step(world)
+
+	assert.EqualValues(t, 1, world.BudgetExhausted)
+	assert.InDelta(t, math.Tanh(1.5), actions[brain.MOVE_X], 0.0001)
+}
+
+func TestDelayedRecurrence(t *testing.T) {
+	world := &World{StepsPerGeneration: 10, Config: DefaultConfig()}
+	world.Config.Recurrence = brain.RecurrenceDelayed
+	peep := &Individual{Brain: loopingBrain(), wasBlocked: true}
+
+	// the neuron fires this step, but the signal only reaches the action on the next one
+	actions := peep.step(world)
+	assert.Zero(t, actions[brain.MOVE_X])
+	assert.Len(t, peep.pending, 1)
+
+	actions = peep.step(world)
+	assert.InDelta(t, math.Tanh(0.5), actions[brain.MOVE_X], 0.0001)
+	assert.Zero(t, world.BudgetExhausted)
+}
+
+func TestNeuronLeak(t *testing.T) {
+	world := &World{StepsPerGeneration: 10, Config: DefaultConfig()}
+	world.Config.Leak = 0.5
+	peep := &Individual{Brain: loopingBrain()}
+	peep.Brain.Neurons[0].Value = 0.8
+
+	peep.step(world)
+	assert.InDelta(t, 0.4, peep.Brain.Neurons[0].Value, 0.0001)
+}
+
+func TestCloneGetsItsOwnIdentity(t *testing.T) {
+	world := &World{Config: DefaultConfig()}
+	parent := &Individual{ID: world.ids().NextIndividual(), Lineage: 7, Genome: genome.MakeRandomGenome(3, rnd), Brain: loopingBrain()}
+
+	child := parent.Clone(world, genome.MUTATION_RATE, rnd)
+	assert.NotEqual(t, parent.ID, child.ID)
+	assert.Equal(t, []int{parent.ID}, child.Parents)
+	assert.Equal(t, 7, child.Lineage)
+}
+
+func TestWorldsSharingIDs(t *testing.T) {
+	ids := &IDs{}
+	one := &World{XSize: 10, YSize: 10, Cells: make([]Cell, 100), Config: DefaultConfig(), IDs: ids}
+	other := &World{XSize: 10, YSize: 10, Cells: make([]Cell, 100), Config: DefaultConfig(), IDs: ids}
+	a := CreateIndividual(one, rnd)
+	b := CreateIndividual(other, rnd)
+	assert.NotEqual(t, a.ID, b.ID)
+	assert.NotEqual(t, a.Lineage, b.Lineage)
+
+	// a world of its own starts counting from the start
+	alone := &World{XSize: 10, YSize: 10, Cells: make([]Cell, 100), Config: DefaultConfig()}
+	assert.Equal(t, 1, CreateIndividual(alone, rnd).ID)
+}
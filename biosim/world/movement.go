@@ -0,0 +1,142 @@
+package world
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/systay/gobiosim/biosim/brain"
+)
+
+// Movement decides how the move actions of an individual turn into a new location
+type Movement uint8
+
+const (
+	// MovementPath adds the move actions up, and walks the cells along the line to where they point,
+	// stopping in front of the first barrier or individual in the way
+	MovementPath Movement = iota
+
+	// MovementJump moves straight to the cell every action points at, jumping over whatever is in between
+	MovementJump
+
+	// MovementProbabilistic moves at most one cell along each axis. Like in biosim4, the strength of the
+	// move actions is the probability that the move happens
+	MovementProbabilistic
+)
+
+var movementNames = map[Movement]string{
+	MovementPath:          "path",
+	MovementJump:          "jump",
+	MovementProbabilistic: "probabilistic",
+}
+
+func (m Movement) String() string {
+	return movementNames[m]
+}
+
+// Set implements flag.Value
+func (m *Movement) Set(s string) error {
+	for movement, name := range movementNames {
+		if name == s {
+			*m = movement
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown movement %q", s)
+}
+
+// Act moves the individual with the given id the way its actions say. The random parts of the move, like
+// MOVE_RANDOM, come from rnd
+func (world *World) Act(id int, actions brain.Actions, rnd *rand.Rand) {
+	individual := world.Peeps[id]
+	individual.DominantAction = dominantAction(actions)
+	start := individual.Location
+	individual.wasBlocked = world.move(id, actions, world.Config.Movement, rnd)
+	if individual.Location != start {
+		individual.heading = world.direction(start, individual.Location)
+	}
+}
+
+// move lets the individual act on its move actions. It returns true if something was in the way
+func (world *World) move(peepIdx int, actions brain.Actions, movement Movement, rnd *rand.Rand) (blocked bool) {
+	if movement == MovementJump {
+		return world.jump(peepIdx, actions, rnd)
+	}
+
+	x, y := actions[brain.MOVE_X], actions[brain.MOVE_Y]
+	if random := actions[brain.MOVE_RANDOM]; random != 0 {
+		if plusMinusOne(rnd) > 0 {
+			x += random
+		} else {
+			y += random
+		}
+	}
+	if movement == MovementProbabilistic {
+		return world.walk(peepIdx, Coord{X: chance(x, rnd), Y: chance(y, rnd)})
+	}
+	return world.walk(peepIdx, Coord{X: int(x * MOVEMENT), Y: int(y * MOVEMENT)})
+}
+
+// jump applies every move action on its own, going straight to the cell it points at
+func (world *World) jump(peepIdx int, actions brain.Actions, rnd *rand.Rand) (blocked bool) {
+	for act, value := range actions {
+		if value == 0 {
+			continue
+		}
+		loc := world.Peeps[peepIdx].Location
+		switch brain.Action(act) {
+		case brain.MOVE_X:
+			loc.X += int(value * MOVEMENT)
+		case brain.MOVE_Y:
+			loc.Y += int(value * MOVEMENT)
+		case brain.MOVE_RANDOM:
+			if plusMinusOne(rnd) > 0 {
+				loc.X += int(value * MOVEMENT)
+			} else {
+				loc.Y += int(value * MOVEMENT)
+			}
+		default:
+			continue
+		}
+		blocked = world.updateLocation(peepIdx, loc)
+	}
+	return blocked
+}
+
+// walk moves the individual one cell at a time along the line to its location plus delta, using
+// Bresenham's line algorithm. It stops at the last free cell before anything in the way
+func (world *World) walk(peepIdx int, delta Coord) (blocked bool) {
+	from := world.Peeps[peepIdx].Location
+	dx, dy := abs(delta.X), abs(delta.Y)
+	sx, sy := sign(delta.X), sign(delta.Y)
+	err := dx - dy
+	var x, y int
+	for x != delta.X || y != delta.Y {
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x += sx
+		}
+		if e2 < dx {
+			err += dx
+			y += sy
+		}
+		if world.updateLocation(peepIdx, Coord{X: from.X + x, Y: from.Y + y}) {
+			return true
+		}
+	}
+	return false
+}
+
+// chance turns the strength of an action into a move of one cell in its direction, with the strength
+// as the probability
+func chance(strength float64, rnd *rand.Rand) int {
+	switch {
+	case rnd.Float64() >= math.Abs(strength):
+		return 0
+	case strength > 0:
+		return 1
+	default:
+		return -1
+	}
+}
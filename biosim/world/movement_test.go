@@ -0,0 +1,59 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/brain"
+)
+
+// rnd gives the tests their random numbers
+var rnd = rand.New(rand.NewSource(1))
+
+// movementWorld has a wall at x=5, and an individual at 2,2 next to a dummy one in the corner
+func movementWorld() (*World, *Individual) {
+	world := &World{XSize: 10, YSize: 10, Cells: make([]Cell, 100), Config: DefaultConfig()}
+	world.barriers = []Area{{TopLeft: Coord{5, 0}, BottomRight: Coord{6, 10}}}
+	world.fillBarriers()
+	world.AddPeep(&Individual{Location: Coord{9, 9}, BirthPlace: Coord{9, 9}})
+	peep := &Individual{Location: Coord{2, 2}, BirthPlace: Coord{2, 2}}
+	world.AddPeep(peep)
+	return world, peep
+}
+
+func TestWalkStopsAtBarrier(t *testing.T) {
+	world, peep := movementWorld()
+	assert.True(t, world.move(1, brain.Actions{1, 0, 0}, MovementPath, rnd))
+	assert.Equal(t, Coord{4, 2}, peep.Location)
+	assert.Equal(t, PeepCell(1), world.Cells[world.OffsetXY(4, 2)])
+	assert.Equal(t, EMPTY, world.Cells[world.OffsetXY(2, 2)])
+}
+
+func TestWalkDiagonal(t *testing.T) {
+	world, peep := movementWorld()
+	assert.False(t, world.move(1, brain.Actions{-0.5, 1, 0}, MovementPath, rnd))
+	assert.Equal(t, Coord{1, 5}, peep.Location)
+
+	world, peep = movementWorld()
+	assert.False(t, world.walk(1, Coord{2, 1}))
+	assert.Equal(t, Coord{4, 3}, peep.Location)
+}
+
+func TestJumpOverBarrier(t *testing.T) {
+	world, peep := movementWorld()
+	peep.Location = Coord{4, 2}
+	world.Cells[world.OffsetXY(2, 2)] = EMPTY
+	world.Cells[world.OffsetXY(4, 2)] = PeepCell(1)
+	assert.False(t, world.move(1, brain.Actions{1, 0, 0}, MovementJump, rnd))
+	assert.Equal(t, Coord{7, 2}, peep.Location)
+}
+
+func TestProbabilisticMove(t *testing.T) {
+	world, peep := movementWorld()
+	assert.False(t, world.move(1, brain.Actions{1, -1, 0}, MovementProbabilistic, rnd))
+	assert.Equal(t, Coord{3, 1}, peep.Location, "full strength always moves one cell")
+
+	assert.False(t, world.move(1, brain.Actions{0, 0, 0}, MovementProbabilistic, rnd))
+	assert.Equal(t, Coord{3, 1}, peep.Location)
+}
@@ -0,0 +1,267 @@
+package world
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+)
+
+type (
+	// Scenario describes the world the individuals live in, and how it changes during the run.
+	// Scenarios are read from JSON files like this one, where the survival area moves to the next edge
+	// every 50 generations, and a barrier is switched on and off every 10:
+	//
+	//	{
+	//	  "size": {"x": 500, "y": 500},
+	//	  "steps_per_generation": 250,
+	//	  "survival_area": {"top_left": {"x": 0, "y": 0}, "bottom_right": {"x": 100, "y": 500}},
+	//	  "barriers": [{"top_left": {"x": 200, "y": 0}, "bottom_right": {"x": 210, "y": 100}}],
+	//	  "events": [
+	//	    {"generation": 50, "every": 50, "rotate_survival_area": true},
+	//	    {"generation": 10, "every": 10, "barrier": 0, "toggle": true}
+	//	  ]
+	//	}
+	Scenario struct {
+		Size               Coord   `json:"size"`
+		StepsPerGeneration int     `json:"steps_per_generation"`
+		SurvivalArea       Area    `json:"survival_area"`
+		Barriers           []Area  `json:"barriers"`
+		Events             []Event `json:"events"`
+	}
+
+	// Event is a change to the world, at a given step of a given generation.
+	// Only the fields that are set are applied
+	Event struct {
+		Generation int `json:"generation"`
+		Step       int `json:"step,omitempty"`
+		// Every repeats the event every n generations after the first time
+		Every int `json:"every,omitempty"`
+
+		// Barrier is the index of the barrier MoveTo and Toggle change
+		Barrier *int `json:"barrier,omitempty"`
+		// MoveTo moves the top left corner of the barrier, keeping its size
+		MoveTo *Coord `json:"move_to,omitempty"`
+		// Toggle removes the barrier if it is there, and puts it back if it isn't
+		Toggle bool `json:"toggle,omitempty"`
+
+		SurvivalArea *Area `json:"survival_area,omitempty"`
+		// RotateSurvivalArea turns the survival area a quarter around the center of the world,
+		// so an area along one edge moves to the next edge
+		RotateSurvivalArea bool `json:"rotate_survival_area,omitempty"`
+
+		// Resize changes the size of the world. Everyone is moved to a random place when that happens,
+		// so it can only be done at the start of a generation
+		Resize *Coord `json:"resize,omitempty"`
+	}
+
+	// Scenarios implements flag.Value, reading a comma separated list of scenario files
+	Scenarios []*Scenario
+)
+
+// DefaultScenario is the world the simulation has always had
+func DefaultScenario() *Scenario {
+	return &Scenario{
+		Size:               Coord{SIZE, SIZE},
+		StepsPerGeneration: STEPS_PER_GEN,
+		SurvivalArea: Area{
+			TopLeft:     Coord{0, 0},
+			BottomRight: Coord{100, SIZE},
+		},
+		Barriers: []Area{{
+			TopLeft:     Coord{200, 0},
+			BottomRight: Coord{210, 100},
+		}, {
+			TopLeft:     Coord{200, SIZE - 100},
+			BottomRight: Coord{210, SIZE},
+		}, {
+			TopLeft:     Coord{220, 90},
+			BottomRight: Coord{240, SIZE - 90},
+		}},
+	}
+}
+
+func loadScenario(filename string) (*Scenario, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	scenario := &Scenario{}
+	if err := json.Unmarshal(data, scenario); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return scenario, nil
+}
+
+// Validate catches scenarios that can't be run, and events that would never happen
+func (s *Scenario) Validate() error {
+	if s.Size.X < 1 || s.Size.Y < 1 {
+		return fmt.Errorf("the world can't be %dx%d", s.Size.X, s.Size.Y)
+	}
+	if s.StepsPerGeneration < 1 {
+		return fmt.Errorf("there must be at least one step per generation")
+	}
+	for idx, event := range s.Events {
+		if event.Barrier != nil && (*event.Barrier < 0 || *event.Barrier >= len(s.Barriers)) {
+			return fmt.Errorf("event %d changes barrier %d, but there are only %d", idx, *event.Barrier, len(s.Barriers))
+		}
+		if event.Barrier == nil && (event.MoveTo != nil || event.Toggle) {
+			return fmt.Errorf("event %d moves or toggles a barrier, but doesn't say which one", idx)
+		}
+		if event.Resize != nil && event.Step != 0 {
+			return fmt.Errorf("event %d resizes the world in the middle of a generation", idx)
+		}
+		if event.Resize != nil && (event.Resize.X < 1 || event.Resize.Y < 1) {
+			return fmt.Errorf("event %d resizes the world to %dx%d", idx, event.Resize.X, event.Resize.Y)
+		}
+		if event.Every < 0 {
+			return fmt.Errorf("event %d repeats every %d generations", idx, event.Every)
+		}
+		if event.Generation < 0 || event.Step < 0 || event.Step >= s.StepsPerGeneration {
+			return fmt.Errorf("event %d happens at step %d of generation %d, but generations have steps 0-%d",
+				idx, event.Step, event.Generation, s.StepsPerGeneration-1)
+		}
+	}
+	return nil
+}
+
+// Smallest returns the number of free cells in the world when it is at its smallest.
+// Barriers can move around, so they count with their full size wherever they are
+func (s *Scenario) Smallest() int {
+	result := s.Size.X * s.Size.Y
+	for _, event := range s.Events {
+		if event.Resize != nil {
+			result = min(result, event.Resize.X*event.Resize.Y)
+		}
+	}
+	for _, barrier := range s.Barriers {
+		result -= max(0, barrier.BottomRight.X-barrier.TopLeft.X) * max(0, barrier.BottomRight.Y-barrier.TopLeft.Y)
+	}
+	return max(0, result)
+}
+
+func (s Scenarios) String() string {
+	return fmt.Sprintf("%d scenarios", len(s))
+}
+
+// Set implements flag.Value
+func (s *Scenarios) Set(value string) error {
+	var result Scenarios
+	for _, filename := range strings.Split(value, ",") {
+		scenario, err := loadScenario(filename)
+		if err != nil {
+			return err
+		}
+		result = append(result, scenario)
+	}
+	*s = result
+	return nil
+}
+
+// happens tells if the event takes place at this step
+func (e Event) happens(generation, step int) bool {
+	if step != e.Step || generation < e.Generation {
+		return false
+	}
+	return generation == e.Generation || (e.Every > 0 && (generation-e.Generation)%e.Every == 0)
+}
+
+// rotate turns the area a quarter clockwise around the center of a world of the given size
+func (a Area) rotate(size Coord) Area {
+	turn := func(c Coord) Coord {
+		return Coord{
+			X: size.X - c.Y*size.X/size.Y,
+			Y: c.X * size.Y / size.X,
+		}
+	}
+	p, q := turn(a.TopLeft), turn(a.BottomRight)
+	return Area{
+		TopLeft:     Coord{X: min(p.X, q.X), Y: min(p.Y, q.Y)},
+		BottomRight: Coord{X: max(p.X, q.X), Y: max(p.Y, q.Y)},
+	}
+}
+
+// NewScenarioWorld creates an empty world laid out like the scenario says
+func NewScenarioWorld(config Config, scenario *Scenario) *World {
+	world := &World{
+		Config:             config,
+		scenario:           scenario,
+		StepsPerGeneration: scenario.StepsPerGeneration,
+		XSize:              scenario.Size.X,
+		YSize:              scenario.Size.Y,
+		Cells:              make([]Cell, scenario.Size.X*scenario.Size.Y),
+		SurvivalArea:       scenario.SurvivalArea,
+		barriers:           append([]Area(nil), scenario.Barriers...),
+		barrierOff:         make([]bool, len(scenario.Barriers)),
+	}
+	world.fillBarriers()
+	return world
+}
+
+// ApplyEvents makes the changes the scenario has planned for this step. When the world is resized,
+// everyone is moved to a random place picked with rnd
+func (world *World) ApplyEvents(generation, step int, rnd *rand.Rand) {
+	if world.scenario == nil {
+		return
+	}
+	changed := false
+	for _, event := range world.scenario.Events {
+		if !event.happens(generation, step) {
+			continue
+		}
+		if event.Barrier != nil {
+			barrier := &world.barriers[*event.Barrier]
+			if event.MoveTo != nil {
+				size := Coord{X: barrier.BottomRight.X - barrier.TopLeft.X, Y: barrier.BottomRight.Y - barrier.TopLeft.Y}
+				barrier.TopLeft = *event.MoveTo
+				barrier.BottomRight = Coord{X: event.MoveTo.X + size.X, Y: event.MoveTo.Y + size.Y}
+			}
+			if event.Toggle {
+				world.barrierOff[*event.Barrier] = !world.barrierOff[*event.Barrier]
+			}
+			changed = true
+		}
+		if event.SurvivalArea != nil {
+			world.SurvivalArea = *event.SurvivalArea
+		}
+		if event.RotateSurvivalArea {
+			world.SurvivalArea = world.SurvivalArea.rotate(Coord{world.XSize, world.YSize})
+		}
+		if event.Resize != nil {
+			world.resize(event.Resize.X, event.Resize.Y, rnd)
+			changed = false
+		}
+	}
+	if changed {
+		world.clearBarriers()
+		world.fillBarriers()
+	}
+}
+
+// clearBarriers removes all barriers from the cells
+func (world *World) clearBarriers() {
+	for idx, cell := range world.Cells {
+		if cell == BARRIER {
+			world.Cells[idx] = EMPTY
+		}
+	}
+}
+
+// resize gives the world a new size. Barriers are put back where they fit, and everyone is moved to a random place
+func (world *World) resize(xSize, ySize int, rnd *rand.Rand) {
+	world.XSize = xSize
+	world.YSize = ySize
+	world.Cells = make([]Cell, xSize*ySize)
+	world.fillBarriers()
+	peeps := world.Peeps
+	world.Peeps = nil
+	for _, peep := range peeps {
+		peep.Location = world.RandomCoord(rnd)
+		peep.BirthPlace = peep.Location
+		world.AddPeep(peep)
+	}
+}
@@ -0,0 +1,115 @@
+package world
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScenario(t *testing.T, content string) string {
+	filename := filepath.Join(t.TempDir(), "scenario.json")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+	return filename
+}
+
+func TestLoadScenario(t *testing.T) {
+	scenario, err := loadScenario(writeScenario(t, `{
+		"size": {"x": 20, "y": 10},
+		"steps_per_generation": 30,
+		"survival_area": {"top_left": {"x": 0, "y": 0}, "bottom_right": {"x": 4, "y": 10}},
+		"barriers": [{"top_left": {"x": 10, "y": 0}, "bottom_right": {"x": 11, "y": 5}}],
+		"events": [{"generation": 5, "every": 5, "barrier": 0, "toggle": true}]
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, Coord{20, 10}, scenario.Size)
+	assert.Equal(t, Area{TopLeft: Coord{10, 0}, BottomRight: Coord{11, 5}}, scenario.Barriers[0])
+	assert.Equal(t, 0, *scenario.Events[0].Barrier)
+
+	for _, broken := range []string{
+		`{"size": {"x": 0, "y": 10}, "steps_per_generation": 30}`,
+		`{"size": {"x": 10, "y": 10}}`,
+		`{"size": {"x": 10, "y": 10}, "steps_per_generation": 30, "events": [{"barrier": 1, "toggle": true}]}`,
+		`{"size": {"x": 10, "y": 10}, "steps_per_generation": 30, "events": [{"toggle": true}]}`,
+		`{"size": {"x": 10, "y": 10}, "steps_per_generation": 30, "events": [{"step": 3, "resize": {"x": 5, "y": 5}}]}`,
+		`{"size": {"x": 10, "y": 10}, "steps_per_generation": 30, "events": [{"step": 30, "toggle": true, "barrier": 0}], "barriers": [{}]}`,
+		`{"size": {"x": 10, "y": 10}, "steps_per_generation": 30, "events": [{"step": -1, "rotate_survival_area": true}]}`,
+		`{"size": {"x": 10, "y": 10}, "steps_per_generation": 30, "events": [{"generation": -5, "rotate_survival_area": true}]}`,
+	} {
+		_, err := loadScenario(writeScenario(t, broken))
+		assert.Error(t, err, broken)
+	}
+}
+
+func TestSmallest(t *testing.T) {
+	scenario := &Scenario{
+		Size: Coord{20, 10},
+		Barriers: []Area{
+			{TopLeft: Coord{10, 0}, BottomRight: Coord{11, 5}},
+			{TopLeft: Coord{0, 0}, BottomRight: Coord{4, 4}},
+		},
+		Events: []Event{{Generation: 10, Resize: &Coord{10, 10}}},
+	}
+	assert.Equal(t, 10*10-5-16, scenario.Smallest())
+
+	scenario.Barriers = append(scenario.Barriers, Area{BottomRight: Coord{20, 20}})
+	assert.Equal(t, 0, scenario.Smallest())
+}
+
+func TestEventHappens(t *testing.T) {
+	once := Event{Generation: 3, Step: 2}
+	assert.True(t, once.happens(3, 2))
+	assert.False(t, once.happens(3, 1))
+	assert.False(t, once.happens(6, 2))
+
+	repeated := Event{Generation: 3, Every: 4}
+	assert.False(t, repeated.happens(0, 0))
+	assert.True(t, repeated.happens(3, 0))
+	assert.False(t, repeated.happens(5, 0))
+	assert.True(t, repeated.happens(11, 0))
+}
+
+func TestRotateSurvivalArea(t *testing.T) {
+	size := Coord{500, 500}
+	left := Area{TopLeft: Coord{0, 0}, BottomRight: Coord{100, 500}}
+	next := left.rotate(size)
+	assert.Equal(t, Area{TopLeft: Coord{0, 0}, BottomRight: Coord{500, 100}}, next)
+	next = next.rotate(size)
+	assert.Equal(t, Area{TopLeft: Coord{400, 0}, BottomRight: Coord{500, 500}}, next)
+	assert.Equal(t, left, next.rotate(size).rotate(size), "four quarter turns bring it back")
+}
+
+func TestApplyEvents(t *testing.T) {
+	barrier := 0
+	scenario := &Scenario{
+		Size:               Coord{10, 6},
+		StepsPerGeneration: 10,
+		Barriers:           []Area{{TopLeft: Coord{5, 0}, BottomRight: Coord{6, 3}}},
+		Events: []Event{
+			{Generation: 1, Barrier: &barrier, Toggle: true},
+			{Generation: 2, Barrier: &barrier, Toggle: true, MoveTo: &Coord{0, 0}},
+			{Generation: 3, Resize: &Coord{20, 20}},
+		},
+	}
+	world := NewScenarioWorld(DefaultConfig(), scenario)
+	assert.Equal(t, BARRIER, world.Cells[world.OffsetXY(5, 0)])
+
+	world.ApplyEvents(1, 0, rnd)
+	assert.Equal(t, EMPTY, world.Cells[world.OffsetXY(5, 0)])
+
+	world.ApplyEvents(2, 0, rnd)
+	assert.Equal(t, EMPTY, world.Cells[world.OffsetXY(5, 0)])
+	assert.Equal(t, BARRIER, world.Cells[world.OffsetXY(0, 2)])
+
+	peep := &Individual{Location: Coord{9, 5}, BirthPlace: Coord{9, 5}}
+	world.AddPeep(&Individual{Location: Coord{9, 4}, BirthPlace: Coord{9, 4}})
+	world.AddPeep(peep)
+	world.ApplyEvents(3, 0, rnd)
+	assert.Equal(t, 20, world.XSize)
+	assert.Len(t, world.Cells, 400)
+	assert.Equal(t, PeepCell(1), world.Cells[world.offset(peep.Location)])
+	assert.Equal(t, BARRIER, world.Cells[world.OffsetXY(0, 2)], "barriers are kept when resizing")
+	assert.Equal(t, scenario.Barriers[0].TopLeft, Coord{5, 0}, "the scenario itself doesn't change")
+}
@@ -0,0 +1,194 @@
+package world
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Species is a kind of individual. Individuals only breed with their own species,
+	// and every species has its own population target and its own rule for who survives
+	Species struct {
+		Name       string
+		Population int
+		Selection  Selection
+	}
+
+	// SpeciesList holds the species living in the world. An individual's species is its index in the list
+	SpeciesList []Species
+
+	// Selection decides which individuals of a species survive a generation
+	Selection uint8
+)
+
+const (
+	// SelectArea lets the individuals in the survival area survive
+	SelectArea Selection = iota
+
+	// SelectOutside lets the individuals outside the survival area survive
+	SelectOutside
+
+	// SelectNear lets the individuals that end up close to another species survive, like a predator
+	SelectNear
+
+	// SelectAway lets the individuals that end up far from other species survive, like prey
+	SelectAway
+)
+
+const (
+	NEAR_DISTANCE        = 3  // individuals closer than this are near each other, for SelectNear and SelectAway
+	SPECIES_FWD_DISTANCE = 10 // how far the SPECIES_FWD sensor can see
+)
+
+var selectionNames = map[Selection]string{
+	SelectArea:    "area",
+	SelectOutside: "outside",
+	SelectNear:    "near",
+	SelectAway:    "away",
+}
+
+func (s Selection) String() string {
+	return selectionNames[s]
+}
+
+// Set implements flag.Value
+func (s *Selection) Set(str string) error {
+	for selection, name := range selectionNames {
+		if name == str {
+			*s = selection
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown selection %q", str)
+}
+
+// DefaultSpecies is the single species the simulation has always had
+func DefaultSpecies() SpeciesList {
+	return SpeciesList{{Name: "peeps", Population: POPULATION, Selection: SelectArea}}
+}
+
+func (l SpeciesList) String() string {
+	var parts []string
+	for _, species := range l {
+		parts = append(parts, fmt.Sprintf("%s:%d:%s", species.Name, species.Population, species.Selection))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value. It takes a comma separated list of name:population:selection triples,
+// and replaces the species with them
+func (l *SpeciesList) Set(s string) error {
+	var result SpeciesList
+	for _, part := range strings.Split(s, ",") {
+		fields := strings.Split(part, ":")
+		if len(fields) != 3 {
+			return fmt.Errorf("expected name:population:selection, got %q", part)
+		}
+		population, err := strconv.Atoi(fields[1])
+		if err != nil || population < 1 {
+			return fmt.Errorf("population of %s must be a positive number, got %q", fields[0], fields[1])
+		}
+		species := Species{Name: fields[0], Population: population}
+		if err := species.Selection.Set(fields[2]); err != nil {
+			return err
+		}
+		result = append(result, species)
+	}
+	*l = result
+	return nil
+}
+
+// Population is the number of individuals of all species together
+func (l SpeciesList) Population() int {
+	total := 0
+	for _, species := range l {
+		total += species.Population
+	}
+	return total
+}
+
+// survives tells if the individual lives on to breed, according to the selection rule of its species
+func (world *World) survives(peep *Individual) bool {
+	switch world.Config.Species[peep.Species].Selection {
+	case SelectOutside:
+		return !world.InArea(world.SurvivalArea, peep.Location.X, peep.Location.Y)
+	case SelectNear:
+		return world.nearOtherSpecies(peep)
+	case SelectAway:
+		return !world.nearOtherSpecies(peep)
+	default:
+		return world.InArea(world.SurvivalArea, peep.Location.X, peep.Location.Y)
+	}
+}
+
+// nearOtherSpecies tells if there is an individual of another species within NEAR_DISTANCE
+func (world *World) nearOtherSpecies(peep *Individual) bool {
+	for y := peep.Location.Y - NEAR_DISTANCE; y <= peep.Location.Y+NEAR_DISTANCE; y++ {
+		for x := peep.Location.X - NEAR_DISTANCE; x <= peep.Location.X+NEAR_DISTANCE; x++ {
+			x, y, ok := world.lookAt(x, y)
+			if !ok {
+				continue
+			}
+			if other := world.PeepAt(x, y); other != nil && other.Species != peep.Species {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PeepAt returns the individual at the location, or nil if there is none
+func (world *World) PeepAt(x, y int) *Individual {
+	cell := world.Cells[world.OffsetXY(x, y)]
+	if cell == EMPTY || cell == BARRIER {
+		return nil
+	}
+	return world.Peeps[PeepID(cell)]
+}
+
+// speciesForward looks ahead in the direction the individual last moved. It sees 1 if the first thing
+// in sight is an individual of its own species, -1 for another species, and 0 for nothing or a barrier
+func (world *World) speciesForward(peep *Individual) float64 {
+	if peep.heading == (Coord{}) {
+		return 0
+	}
+	x, y := peep.Location.X, peep.Location.Y
+	for distance := 0; distance < SPECIES_FWD_DISTANCE; distance++ {
+		var ok bool
+		x, y, ok = world.lookAt(x+peep.heading.X, y+peep.heading.Y)
+		if !ok {
+			return 0
+		}
+		cell := world.Cells[world.OffsetXY(x, y)]
+		switch {
+		case cell == BARRIER:
+			return 0
+		case cell == EMPTY:
+			continue
+		case world.Peeps[PeepID(cell)].Species == peep.Species:
+			return 1
+		default:
+			return -1
+		}
+	}
+	return 0
+}
+
+// BySpecies splits the individuals up by species
+func BySpecies(peeps []*Individual, species int) [][]*Individual {
+	result := make([][]*Individual, species)
+	for _, peep := range peeps {
+		result[peep.Species] = append(result[peep.Species], peep)
+	}
+	return result
+}
+
+// CountSpecies counts the individuals of every species, by name
+func CountSpecies(peeps []*Individual, species SpeciesList) map[string]int {
+	result := map[string]int{}
+	for _, peep := range peeps {
+		result[species[peep.Species].Name]++
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpeciesList_Set(t *testing.T) {
+	var species SpeciesList
+	require.NoError(t, species.Set("prey:700:away,wolves:300:near"))
+	assert.Equal(t, SpeciesList{
+		{Name: "prey", Population: 700, Selection: SelectAway},
+		{Name: "wolves", Population: 300, Selection: SelectNear},
+	}, species)
+	assert.Equal(t, "prey:700:away,wolves:300:near", species.String())
+	assert.Equal(t, 1000, species.Population())
+
+	assert.Error(t, species.Set("prey:700"))
+	assert.Error(t, species.Set("prey:none:away"))
+	assert.Error(t, species.Set("prey:700:hiding"))
+}
+
+// speciesWorld has a prey and a predator species
+func speciesWorld() *World {
+	world := testWorld()
+	world.Config = DefaultConfig()
+	world.Config.Species = SpeciesList{
+		{Name: "prey", Population: 10, Selection: SelectAway},
+		{Name: "wolves", Population: 10, Selection: SelectNear},
+	}
+	return world
+}
+
+func TestSelection_NearAndAway(t *testing.T) {
+	world := speciesWorld()
+	caught := &Individual{Species: 0, Location: Coord{0, 0}, BirthPlace: Coord{0, 0}}
+	wolf := &Individual{Species: 1, Location: Coord{2, 2}, BirthPlace: Coord{2, 2}}
+	escaped := &Individual{Species: 0, Location: Coord{9, 0}, BirthPlace: Coord{9, 0}}
+	hungry := &Individual{Species: 1, Location: Coord{8, 5}, BirthPlace: Coord{8, 5}}
+	for _, peep := range []*Individual{caught, wolf, escaped, hungry} {
+		world.AddPeep(peep)
+	}
+
+	assert.False(t, world.survives(caught))
+	assert.True(t, world.survives(wolf))
+	assert.True(t, world.survives(escaped))
+	assert.False(t, world.survives(hungry))
+}
+
+func TestSpeciesForward(t *testing.T) {
+	world := speciesWorld()
+	looking := &Individual{Species: 0, Location: Coord{0, 4}, BirthPlace: Coord{0, 4}}
+	friend := &Individual{Species: 0, Location: Coord{3, 4}, BirthPlace: Coord{3, 4}}
+	foe := &Individual{Species: 1, Location: Coord{0, 1}, BirthPlace: Coord{0, 1}}
+	for _, peep := range []*Individual{looking, friend, foe} {
+		world.AddPeep(peep)
+	}
+
+	assert.Equal(t, 0.0, world.speciesForward(looking), "hasn't moved yet, so it has no direction to look in")
+	looking.heading = Coord{X: 1}
+	assert.Equal(t, 1.0, world.speciesForward(looking))
+	looking.heading = Coord{Y: -1}
+	assert.Equal(t, -1.0, world.speciesForward(looking))
+	looking.heading = Coord{X: -1}
+	assert.Equal(t, 0.0, world.speciesForward(looking))
+}
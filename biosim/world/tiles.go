@@ -0,0 +1,31 @@
+package world
+
+const (
+	MAX_REACH  = 2 * MOVEMENT // the furthest an individual can get along the x axis in one step
+	TILE_WIDTH = 16           // the width of the strips moves are made in. Must be at least 2 * MAX_REACH
+)
+
+// TilePhases splits the world into strips of TILE_WIDTH, and puts the individuals in the strip they are in.
+// Strips are grouped into phases, where no individual can reach a cell an individual in another strip of the same
+// phase can reach, so the strips of a phase can be moved in parallel. Every strip lists its individuals by id, which
+// makes the outcome of two individuals going for the same cell the same every time.
+// A strip must be at least TILE_WIDTH wide to keep the strips on either side of it apart, also across the edge of
+// a torus, so the cells left over when the width isn't a multiple of TILE_WIDTH go into the last strip
+func (world *World) TilePhases() [][][]int {
+	tiles := make([][]int, max(1, world.XSize/TILE_WIDTH))
+	for id, peep := range world.Peeps {
+		tile := min(peep.Location.X/TILE_WIDTH, len(tiles)-1)
+		tiles[tile] = append(tiles[tile], id)
+	}
+
+	phases := make([][][]int, 3)
+	for idx, tile := range tiles {
+		phase := idx % 2
+		if world.Config.Boundary == BoundaryTorus && len(tiles)%2 == 1 && idx == len(tiles)-1 {
+			// the last strip borders the first one, so it can't share a phase with it
+			phase = 2
+		}
+		phases[phase] = append(phases[phase], tile)
+	}
+	return phases
+}
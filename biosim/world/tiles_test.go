@@ -0,0 +1,60 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTilePhases(t *testing.T) {
+	assert.GreaterOrEqual(t, TILE_WIDTH, 2*MAX_REACH, "individuals in strips of the same phase could meet")
+
+	world := &World{XSize: 5 * TILE_WIDTH, YSize: 10, Cells: make([]Cell, 50*TILE_WIDTH), Config: DefaultConfig()}
+	for x := 0; x < world.XSize; x += TILE_WIDTH / 2 {
+		world.AddPeep(&Individual{Location: Coord{x, 0}, BirthPlace: Coord{x, 0}})
+	}
+	phases := world.TilePhases()
+	assert.Equal(t, [][]int{{0, 1}, {4, 5}, {8, 9}}, phases[0])
+	assert.Equal(t, [][]int{{2, 3}, {6, 7}}, phases[1])
+	assert.Empty(t, phases[2])
+
+	world.Config.Boundary = BoundaryTorus
+	phases = world.TilePhases()
+	assert.Equal(t, [][]int{{0, 1}, {4, 5}}, phases[0])
+	assert.Equal(t, [][]int{{8, 9}}, phases[2], "the last strip is next to the first one on a torus")
+}
+
+func TestTilePhasesOnTorus(t *testing.T) {
+	// every width, with an individual in every column, and strips of the same phase must never reach the same cell
+	for width := 1; width < 6*TILE_WIDTH; width++ {
+		world := &World{XSize: width, YSize: 1, Cells: make([]Cell, width), Config: DefaultConfig()}
+		world.Config.Boundary = BoundaryTorus
+		for x := 0; x < width; x++ {
+			world.AddPeep(&Individual{Location: Coord{x, 0}, BirthPlace: Coord{x, 0}})
+		}
+		for _, phase := range world.TilePhases() {
+			reachedBy := map[int]int{}
+			for strip, ids := range phase {
+				for _, id := range ids {
+					for dx := -MAX_REACH; dx <= MAX_REACH; dx++ {
+						x := ((world.Peeps[id].Location.X+dx)%width + width) % width
+						if other, ok := reachedBy[x]; ok && other != strip {
+							t.Fatalf("width %d: strips %d and %d can both reach x=%d", width, other, strip, x)
+						}
+						reachedBy[x] = strip
+					}
+				}
+			}
+		}
+	}
+
+	world := &World{XSize: 4*TILE_WIDTH + 4, YSize: 10, Cells: make([]Cell, 10*(4*TILE_WIDTH+4)), Config: DefaultConfig()}
+	world.Config.Boundary = BoundaryTorus
+	for x := 0; x < world.XSize; x += TILE_WIDTH / 2 {
+		world.AddPeep(&Individual{Location: Coord{x, 0}, BirthPlace: Coord{x, 0}})
+	}
+	phases := world.TilePhases()
+	assert.Equal(t, [][]int{{0, 1}, {4, 5}}, phases[0])
+	assert.Equal(t, [][]int{{2, 3}, {6, 7, 8}}, phases[1], "the last 4 cells belong to the last strip")
+	assert.Empty(t, phases[2])
+}
@@ -0,0 +1,168 @@
+// Package world holds the grid the individuals live in, and how they sense, move and survive in it
+package world
+
+import "math/rand"
+
+type (
+	World struct {
+		StepsPerGeneration int
+		XSize              int
+		YSize              int
+		Cells              []Cell
+		Peeps              []*Individual
+		SurvivalArea       Area
+		barriers           []Area
+		Config             Config
+
+		// IDs hands out the ids of the individuals born in the world. nil gives the world ids of its own
+		IDs *IDs
+
+		// scenario plans the changes to the world during the run. nil means the world never changes
+		scenario *Scenario
+		// barrierOff tells which barriers have been toggled away
+		barrierOff []bool
+
+		// number of times an individual ran out of signal budget during the current generation.
+		// updated concurrently, so only touch it through sync/atomic
+		BudgetExhausted int64
+	}
+)
+
+const (
+	MOVEMENT      = 3
+	POPULATION    = 1000
+	STEPS_PER_GEN = 250
+	SIZE          = 500
+)
+
+// A cell is EMPTY, a BARRIER, or holds the id of the individual in it plus one, see PeepCell
+const EMPTY Cell = 0
+
+// MAX_PEEPS is the number of individuals that fit in a world. The cell values between EMPTY and BARRIER
+// are all the ids there are
+const MAX_PEEPS = int(BARRIER) - 1
+
+// PeepCell is the cell value of the individual with the given id
+func PeepCell(id int) Cell {
+	return Cell(id + 1)
+}
+
+// PeepID is the id of the individual in a cell that is neither EMPTY nor a BARRIER
+func PeepID(cell Cell) int {
+	return int(cell) - 1
+}
+
+func (world *World) AddPeep(individual *Individual) {
+	id := len(world.Peeps)
+	world.Peeps = append(world.Peeps, individual)
+	offset := world.offset(individual.BirthPlace)
+	world.Cells[offset] = PeepCell(id)
+}
+
+// ids returns the IDs of the world, making them the first time they are needed
+func (world *World) ids() *IDs {
+	if world.IDs == nil {
+		world.IDs = &IDs{}
+	}
+	return world.IDs
+}
+
+func (world *World) offset(place Coord) int {
+	return world.OffsetXY(place.X, place.Y)
+}
+
+func (world *World) OffsetXY(x, y int) int {
+	return y*world.XSize + x
+}
+
+func limit(v, max int) int {
+	if v > max {
+		return max
+	}
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+func (world *World) updateLocation(peepIdx int, location Coord) (blocked bool) {
+	// contain ourselves to the given world
+	location = world.contain(location)
+
+	// first we check if the spot is taken. if it isn't, we just ignore the location change
+	newOffset := world.offset(location)
+	if world.Cells[newOffset] != EMPTY {
+		return true
+	}
+
+	// if the spot is empty, we can move the peep to the new location
+	oldOffset := world.offset(world.Peeps[peepIdx].Location)
+	world.Cells[oldOffset] = EMPTY
+	world.Cells[newOffset] = PeepCell(peepIdx)
+	world.Peeps[peepIdx].Location = location
+	return false
+}
+
+func (world *World) clearAll() {
+	for _, peep := range world.Peeps {
+		world.Cells[world.offset(peep.Location)] = EMPTY
+	}
+	world.Peeps = nil
+}
+
+// RandomCoord finds a free cell, using rnd for the random numbers
+func (world *World) RandomCoord(rnd *rand.Rand) Coord {
+	location := Coord{
+		X: rnd.Intn(world.XSize),
+		Y: rnd.Intn(world.YSize),
+	}
+
+	newOffset := world.offset(location)
+	if world.Cells[newOffset] != EMPTY {
+		return world.RandomCoord(rnd)
+	}
+
+	return location
+}
+
+// Cull removes everyone from the world, and returns the individuals that survived the generation.
+// The size penalty is drawn from rnd
+func (world *World) Cull(rnd *rand.Rand) []*Individual {
+	// selection can depend on who is around, so the world is cleared only after everyone has been judged
+	peeps := world.Peeps
+
+	var survivors []*Individual
+	for _, peep := range peeps {
+		if !world.survives(peep) {
+			continue
+		}
+		if rnd.Float64() < world.Config.SizePenalty*float64(len(peep.Genome.Genes)) {
+			continue
+		}
+		survivors = append(survivors, peep)
+	}
+	world.clearAll()
+	return survivors
+}
+
+// fillBarriers puts the barriers that are switched on into the cells. The parts of barriers that end up
+// outside the world are left out, or wrapped around on a torus, and so are the cells where an individual already is
+func (world *World) fillBarriers() {
+	for idx, barrier := range world.barriers {
+		if idx < len(world.barrierOff) && world.barrierOff[idx] {
+			continue
+		}
+		for x := barrier.TopLeft.X; x < barrier.BottomRight.X; x++ {
+			for y := barrier.TopLeft.Y; y < barrier.BottomRight.Y; y++ {
+				x, y, ok := world.lookAt(x, y)
+				if !ok {
+					continue
+				}
+				idx := world.OffsetXY(x, y)
+				if world.Cells[idx] == EMPTY {
+					world.Cells[idx] = BARRIER
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/genome"
+)
+
+func testWorld() *World {
+	world := &World{
+		XSize: 10,
+		YSize: 6,
+		Cells: make([]Cell, 60),
+		SurvivalArea: Area{
+			TopLeft:     Coord{0, 0},
+			BottomRight: Coord{2, 6},
+		},
+		barriers: []Area{{
+			TopLeft:     Coord{5, 0},
+			BottomRight: Coord{6, 3},
+		}},
+	}
+	world.fillBarriers()
+	return world
+}
+
+func TestFirstPeepTakesItsCell(t *testing.T) {
+	world := &World{XSize: 10, YSize: 10, Cells: make([]Cell, 100), Config: DefaultConfig()}
+	first := &Individual{Location: Coord{3, 3}, BirthPlace: Coord{3, 3}}
+	world.AddPeep(first)
+
+	assert.Equal(t, first, world.PeepAt(3, 3))
+	assert.NotEqual(t, Coord{3, 3}, world.RandomCoord(rnd))
+	other := &Individual{Location: Coord{3, 4}, BirthPlace: Coord{3, 4}}
+	world.AddPeep(other)
+	assert.True(t, world.updateLocation(1, Coord{3, 3}), "can't move into the first individual")
+}
+
+func TestCellIDs(t *testing.T) {
+	assert.Equal(t, 0, PeepID(PeepCell(0)))
+	assert.Equal(t, MAX_PEEPS-1, PeepID(PeepCell(MAX_PEEPS-1)))
+	assert.NotEqual(t, EMPTY, PeepCell(0))
+	assert.NotEqual(t, BARRIER, PeepCell(MAX_PEEPS-1))
+}
+
+func TestDominantAction(t *testing.T) {
+	assert.Equal(t, brain.MOVE_Y, dominantAction(brain.Actions{0.2, -0.5, 0.1}))
+	assert.Equal(t, brain.NUM_ACTIONS, dominantAction(brain.Actions{0, 0, 0}))
+}
+
+func TestCullSizePenalty(t *testing.T) {
+	world := &World{XSize: 10, YSize: 10, Cells: make([]Cell, 100), Config: DefaultConfig()}
+	world.SurvivalArea = Area{BottomRight: Coord{10, 10}}
+	small := &Individual{}
+	big := &Individual{Genome: genome.MakeRandomGenome(20, rnd)}
+	world.Peeps = []*Individual{small, big}
+
+	// the big one is sure to die, and the one without genes is sure to survive
+	world.Config.SizePenalty = 0.05
+	assert.Equal(t, []*Individual{small}, world.Cull(rnd))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/systay/gobiosim/biosim/brain"
+	"github.com/systay/gobiosim/biosim/world"
+)
+
+func dumpIndividuals(generation int, peeps []*world.Individual) {
+	var data []string
+	var brains []*brain.NeuralNet
+	var counts []int
+	seen := map[string]int{}
+	for _, peep := range peeps {
+		brain := peep.Brain.String() + "\n"
+		if idx, ok := seen[brain]; ok {
+			data[idx] += "*"
+			counts[idx]++
+			continue
+		}
+
+		seen[brain] = len(data)
+		expressed, dormant := peep.Expression()
+		data = append(data, fmt.Sprintf("expressed genes: %d, dormant genes: %d\n", expressed, dormant)+brain)
+		brains = append(brains, peep.Brain)
+		counts = append(counts, 1)
+	}
+	output := strings.Join(data, "\n")
+	err := os.MkdirAll(fmt.Sprintf("%04d", generation), os.ModePerm)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = os.WriteFile(fmt.Sprintf("%04d/peeps.txt", generation), []byte(output), os.ModePerm)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dumpBrains(generation, brains, counts)
+}
+
+// dumpBrains writes the distinct brains of a generation as JSON, and the most common ones as Graphviz files
+func dumpBrains(generation int, brains []*brain.NeuralNet, counts []int) {
+	type brainCount struct {
+		Count int              `json:"count"`
+		Brain *brain.NeuralNet `json:"brain"`
+	}
+	var all []brainCount
+	for idx, brain := range brains {
+		all = append(all, brainCount{Count: counts[idx], Brain: brain})
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		return all[i].Count > all[j].Count
+	})
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = os.WriteFile(fmt.Sprintf("%04d/brains.json", generation), data, os.ModePerm)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for idx, brain := range all {
+		if idx == TOP_BRAINS {
+			break
+		}
+		err := os.WriteFile(fmt.Sprintf("%04d/brain%02d.dot", generation, idx), []byte(brain.Brain.DOT()), os.ModePerm)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+}
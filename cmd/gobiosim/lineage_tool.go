@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/systay/gobiosim/biosim"
+)
+
+// lineageTool reads a lineage log and prints the ancestry tree of the survivors
+func lineageTool(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("lineage", flag.ContinueOnError)
+	format := flags.String("format", "newick", "output format: newick or json")
+	generation := flags.Int("generation", -1, "the generation whose survivors to trace, -1 for the last one")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: gobiosim lineage [-format newick|json] [-generation n] lineage.jsonl")
+	}
+
+	f, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	births, survivors, err := biosim.ReadLineageLog(f, *generation)
+	if err != nil {
+		return err
+	}
+	roots, err := biosim.Ancestry(births, survivors)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "newick":
+		_, err = io.WriteString(out, biosim.Newick(roots))
+		return err
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(roots)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLineage has two random individuals, 1 and 2. 1 gets children 3 and 4, 3 gets child 5.
+// After generation 1, 2 and 4 survive, and after generation 2, 4 and 5 survive
+const testLineage = `{"birth":{"id":1,"generation":0,"lineage":1}}
+{"birth":{"id":2,"generation":0,"lineage":2}}
+{"cull":{"generation":0,"survivors":[1,2]}}
+{"birth":{"id":3,"parents":[1],"generation":1,"lineage":1,"mutations":["weight 1"]}}
+{"birth":{"id":4,"parents":[1],"generation":1,"lineage":1}}
+{"cull":{"generation":1,"survivors":[3,4]}}
+{"birth":{"id":5,"parents":[3],"generation":2,"lineage":1,"mutations":["insert 0"]}}
+{"cull":{"generation":2,"survivors":[4,5]}}
+`
+
+func writeTestLineage(t *testing.T) string {
+	filename := filepath.Join(t.TempDir(), "lineage.jsonl")
+	require.NoError(t, os.WriteFile(filename, []byte(testLineage), 0o644))
+	return filename
+}
+
+func TestLineageTool(t *testing.T) {
+	filename := writeTestLineage(t)
+
+	var out bytes.Buffer
+	require.NoError(t, lineageTool([]string{filename}, &out))
+	assert.Equal(t, "((5:1)3:1,4:1)1:0;\n", out.String())
+
+	out.Reset()
+	require.NoError(t, lineageTool([]string{"-generation", "0", "-format", "json", filename}, &out))
+	assert.JSONEq(t, `[{"id": 1, "generation": 0, "lineage": 1, "survivor": true}, {"id": 2, "generation": 0, "lineage": 2, "survivor": true}]`, out.String())
+}
+
+func TestLineageToolErrors(t *testing.T) {
+	filename := writeTestLineage(t)
+	assert.Error(t, lineageTool([]string{"-generation", "7", filename}, &bytes.Buffer{}))
+	assert.Error(t, lineageTool([]string{"-format", "xml", filename}, &bytes.Buffer{}))
+	assert.Error(t, lineageTool([]string{}, &bytes.Buffer{}))
+}
@@ -0,0 +1,251 @@
+// Command gobiosim runs a simulation from the command line, showing its progress in the terminal and
+// writing movies of the generations
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/cheggaaa/pb/v3"
+	"github.com/systay/gobiosim/biosim"
+	"github.com/systay/gobiosim/biosim/genome"
+	"github.com/systay/gobiosim/biosim/render"
+	"github.com/systay/gobiosim/biosim/world"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+)
+
+// options holds the settings of a run that are about the command, and not about the simulation
+type options struct {
+	// Output is the format the movies of dumped generations are written in
+	Output render.OutputFormat
+
+	// FrameEvery keeps only every n:th step as a frame in the movies
+	FrameEvery int
+
+	// FrameScale resizes the frames. 0.5 makes a movie of half the width and height of the world
+	FrameScale float64
+
+	// RenderWorkers is the number of goroutines rendering movie frames in the background
+	RenderWorkers int
+
+	// RenderQueue is the number of frames that can wait for rendering and writing before the simulation
+	// has to wait for them
+	RenderQueue int
+
+	// ViewEvery draws the world in the terminal every n:th step, instead of showing a progress bar.
+	// 0 turns the terminal view off
+	ViewEvery int
+
+	// ViewWidth is the number of characters the width of the world is squeezed into in the terminal view
+	ViewWidth int
+
+	// HTTPAddr is the address the dashboard and control api is served on. Empty means no server
+	HTTPAddr string
+
+	// Generations is the number of generations the simulation runs for
+	Generations int
+
+	// Headless runs without movies, dumps of individuals and the progress bar, like the runs of a sweep
+	Headless bool
+
+	// StatsFile is the file the stats of every generation are written to as JSON when the run ends.
+	// Empty means they are not written
+	StatsFile string
+}
+
+const (
+	GENERATIONS  = 1000
+	RENDER_QUEUE = 64
+	VIEW_WIDTH   = 100
+	DUMP_EVERY   = 100
+	TOP_BRAINS   = 10 // the number of brains that are written as Graphviz files when dumping a generation
+)
+
+func defaultOptions() options {
+	return options{
+		Output:        render.OutputPNG,
+		FrameEvery:    1,
+		FrameScale:    1,
+		RenderWorkers: runtime.NumCPU(),
+		RenderQueue:   RENDER_QUEUE,
+		ViewWidth:     VIEW_WIDTH,
+		Generations:   GENERATIONS,
+	}
+}
+
+// validate catches the settings that would crash the run later on, so it fails before it starts
+func (o options) validate() error {
+	if o.FrameEvery < 1 {
+		return fmt.Errorf("-frame-every must be at least 1, got %d", o.FrameEvery)
+	}
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "lineage" {
+		if err := lineageTool(os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "sweep" {
+		if err := sweepTool(os.Args[2:], os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	config := biosim.DefaultConfig()
+	opts := defaultOptions()
+	flag.IntVar(&config.SignalBudget, "signal-budget", config.SignalBudget, "max number of neuron firings per individual and step")
+	flag.Var(&config.Recurrence, "recurrence", "how neuron firings propagate: budget or delayed")
+	flag.Float64Var(&config.Leak, "leak", config.Leak, "fraction of its value a neuron loses every step")
+	flag.BoolVar(&config.ResetAtBirth, "reset-at-birth", config.ResetAtBirth, "start offspring with quiet neurons")
+	flag.BoolVar(&config.ResetEachGeneration, "reset-each-generation", config.ResetEachGeneration, "quiet all neurons when a generation starts")
+	flag.Var(&opts.Output, "output", "format of the generation movies: png, gif or apng")
+	flag.IntVar(&opts.FrameEvery, "frame-every", opts.FrameEvery, "only keep every n:th step in the movies")
+	flag.Float64Var(&opts.FrameScale, "frame-scale", opts.FrameScale, "scale factor for movie frames")
+	flag.Var(&config.Coloring, "coloring", "what the colors of individuals in movies show: black, genome, lineage, age, action or species")
+	flag.IntVar(&config.Workers, "workers", config.Workers, "number of goroutines the individuals think and move on")
+	flag.IntVar(&opts.RenderWorkers, "render-workers", opts.RenderWorkers, "number of goroutines rendering movie frames, 0 for one per cpu")
+	flag.IntVar(&opts.RenderQueue, "render-queue", opts.RenderQueue, "max number of movie frames waiting to be rendered and written")
+	flag.IntVar(&opts.ViewEvery, "view-every", opts.ViewEvery, "draw the world in the terminal every n:th step, 0 to not draw it")
+	flag.IntVar(&opts.ViewWidth, "view-width", opts.ViewWidth, "number of characters the world is drawn in")
+	flag.StringVar(&opts.HTTPAddr, "http", opts.HTTPAddr, "address to serve the dashboard and control api on, like localhost:8080")
+	flag.StringVar(&config.CheckpointDir, "checkpoint-dir", config.CheckpointDir, "directory to write checkpoints to")
+	flag.StringVar(&config.LineageLog, "lineage-log", config.LineageLog, "file to log every birth and cull to, for the lineage tool")
+	flag.Var(config.MutationRates, "mutation-rates", "comma separated operator=rate pairs, relative to the mutation rate. operators: "+genome.MutationOperatorNames())
+	flag.Var(&config.MutationSchedule, "mutation-schedule", "how the mutation rate changes: fixed or adaptive")
+	flag.IntVar(&config.GenomeBounds.MinGenes, "min-genes", config.GenomeBounds.MinGenes, "min number of genes in a genome")
+	flag.IntVar(&config.GenomeBounds.MaxGenes, "max-genes", config.GenomeBounds.MaxGenes, "max number of genes in a genome")
+	flag.IntVar(&config.GenomeBounds.MinNeurons, "min-neurons", config.GenomeBounds.MinNeurons, "min number of neurons in a genome")
+	flag.IntVar(&config.GenomeBounds.MaxNeurons, "max-neurons", config.GenomeBounds.MaxNeurons, "max number of neurons in a genome")
+	flag.IntVar(&config.InitialGenes, "initial-genes", config.InitialGenes, "max number of genes in randomly created genomes")
+	flag.Float64Var(&config.SizePenalty, "size-penalty", config.SizePenalty, "chance per gene that a survivor dies anyway")
+	flag.Var(&config.Immigration, "immigration", "where immigrants come from: none, random or pool")
+	flag.Float64Var(&config.ImmigrationRate, "immigration-rate", config.ImmigrationRate, "share of every new generation that are immigrants")
+	flag.StringVar(&config.GenePool, "gene-pool", config.GenePool, "checkpoint file to take the genomes of immigrants from, for -immigration pool")
+	flag.IntVar(&config.Islands, "islands", config.Islands, "number of worlds with a population of their own")
+	flag.IntVar(&config.Migrants, "migrants", config.Migrants, "number of survivors that leave every island when migrating")
+	flag.IntVar(&config.MigrateEvery, "migrate-every", config.MigrateEvery, "let migrants move every n:th generation, 0 to never")
+	flag.Var(&config.Topology, "topology", "where migrants can go: ring or full")
+	flag.Var(&config.Species, "species", "comma separated name:population:selection triples, selection being area, outside, near or away")
+	flag.Var(&config.Reproduction, "reproduction", "how survivors share the offspring: copy, or shared between clusters of similar genomes")
+	flag.Var(&config.Movement, "movement", "how individuals move: path, jump or probabilistic")
+	flag.Var(&config.Boundary, "boundary", "what the edges of the world do: clamp, torus or reflect")
+	flag.Var(&config.Scenarios, "scenario", "comma separated scenario files describing the world and how it changes. islands take turns using them")
+	flag.IntVar(&opts.Generations, "generations", opts.Generations, "number of generations to run")
+	flag.Int64Var(&config.MutationRate, "mutation-rate", config.MutationRate, "chance of a mutation to start with, x in 1000")
+	flag.Int64Var(&config.Seed, "seed", config.Seed, "seed for the random numbers, 0 to pick one from the clock")
+	flag.BoolVar(&opts.Headless, "headless", opts.Headless, "run without movies, dumps and progress bar")
+	flag.StringVar(&opts.StatsFile, "stats-file", opts.StatsFile, "file to write the stats of every generation to as JSON when the run ends")
+	flag.Parse()
+	if err := opts.validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// the seed is picked here instead of by the simulation, so it can be told, and the run made again
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	fmt.Fprintf(os.Stderr, "rand seed: %d\n", config.Seed)
+
+	frames := render.NewRenderer(opts.RenderWorkers, opts.RenderQueue, opts.FrameScale)
+	bar := pb.ProgressBarTemplate(`Generation {{counters . }} Survivors: {{string . "survivors"}} Exhausted: {{string . "exhausted"}} Expressed: {{string . "expressed"}} Mutation rate: {{string . "rate"}} Frames: {{string . "frames"}} {{bar . }} {{percent . }} {{rtime . "ETA %s"}}`).New(opts.Generations)
+	var view *render.TerminalView
+	switch {
+	case opts.Headless:
+		bar.SetWriter(io.Discard)
+	case opts.ViewEvery > 0:
+		// the view shows the progress bar itself, so the bar must not draw over it
+		var err error
+		view, err = render.NewTerminalView(os.Stdout, opts.ViewWidth)
+		if err != nil {
+			log.Fatal(err)
+		}
+		bar.SetWriter(io.Discard)
+	}
+	dumping := func(generation int) bool {
+		return !opts.Headless && generation%DUMP_EVERY == 0
+	}
+
+	// the movies and the terminal view show the first island
+	var s *biosim.Simulation
+	var movie render.MovieWriter
+	showStep := func(step int) {
+		w := s.World()
+		if movie != nil && step%opts.FrameEvery == 0 {
+			frames.Frame(movie, step, w, render.PeepColors(w, config.Coloring))
+		}
+		if view != nil && step%opts.ViewEvery == 0 {
+			status := fmt.Sprintf("Step %d/%d Population: %d %s", step, w.StepsPerGeneration, len(w.Peeps), bar.String())
+			if err := view.Draw(w, render.PeepColors(w, config.Coloring), status); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+	showGeneration := func(stats biosim.GenerationStats, survivors []*world.Individual) {
+		bar.Set("expressed", fmt.Sprintf("%.0f%%", stats.ExpressedRatio*100))
+		if config.Islands > 1 {
+			bar.Set("survivors", fmt.Sprintf("%d (%s)", stats.Survivors, s.SurvivorsPerIsland()))
+		} else {
+			bar.Set("survivors", fmt.Sprintf("%d", stats.Survivors))
+		}
+		bar.Set("exhausted", fmt.Sprintf("%d", stats.BudgetExhausted))
+		bar.Set("rate", fmt.Sprintf("%d", s.MutationRate()))
+		if dumping(stats.Generation) {
+			dumpIndividuals(stats.Generation, survivors)
+		}
+	}
+	s, err := biosim.New(config, biosim.OnStep(showStep), biosim.OnGeneration(showGeneration))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if opts.HTTPAddr != "" {
+		go func() {
+			log.Fatal(http.ListenAndServe(opts.HTTPAddr, s.Handler()))
+		}()
+	}
+
+	finish := func() {
+		bar.Finish()
+		if err := frames.Close(); err != nil {
+			log.Fatal(err)
+		}
+		if err := s.Close(); err != nil {
+			log.Fatal(err)
+		}
+		if err := biosim.WriteStats(opts.StatsFile, s.History()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	bar.Start()
+	for generation := 0; generation < opts.Generations; generation++ {
+		bar.Increment()
+		movie = nil
+		if dumping(generation) {
+			movie, err = render.NewMovieWriter(opts.Output, generation)
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		stats := s.RunGeneration()
+		if movie != nil {
+			frames.Finish(movie)
+			bar.Set("frames", frames.Throughput())
+		}
+
+		if stats.Survivors == 0 {
+			finish()
+			fmt.Println("extinction")
+			os.Exit(0)
+		}
+	}
+	finish()
+	fmt.Print(biosim.MutationSummary(s.History()))
+	fmt.Println("done")
+}
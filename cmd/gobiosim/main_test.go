@@ -0,0 +1,27 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RUN_MAIN makes the test binary run the command instead of the tests, so tests can start it as a process
+const RUN_MAIN = "GOBIOSIM_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(RUN_MAIN) != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestOptionsValidate(t *testing.T) {
+	opts := defaultOptions()
+	assert.NoError(t, opts.validate())
+
+	opts.FrameEvery = 0
+	assert.Error(t, opts.validate())
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/systay/gobiosim/biosim"
+)
+
+type (
+	// sweepParam is a flag of the simulation, and the values a sweep tries for it
+	sweepParam struct {
+		flag   string
+		values []string
+	}
+
+	// sweepParams implements flag.Value. Every -vary adds a parameter, like -vary 'mutation-rate=10 50 100'.
+	// Values are separated by spaces, and a value like 10:100:10 is the range from 10 to 100 in steps of 10
+	sweepParams []sweepParam
+
+	// sweepRun is one simulation of a sweep: a value for every parameter, and which replicate it is
+	sweepRun struct {
+		values    []string
+		replicate int
+		seed      int64
+	}
+
+	// sweepResult is what a run of a sweep came to
+	sweepResult struct {
+		generations int
+		// finalSurvival is the share of the population that survived the last generation
+		finalSurvival float64
+		// timeToThreshold is the first generation where the survival rate reached the threshold, -1 if it never did
+		timeToThreshold int
+	}
+
+	// runner runs the simulation with the given flags, and returns the stats of every generation
+	runner func(args []string) ([]biosim.GenerationStats, error)
+)
+
+const SWEEP_GENERATIONS = 100
+
+// unsharedFlags write to a file or listen on an address, so the runs of a sweep, which run at the same time,
+// would get in each other's way if they had them in common
+var unsharedFlags = []string{"lineage-log", "checkpoint-dir", "stats-file", "http"}
+
+func (p sweepParams) String() string {
+	var parts []string
+	for _, param := range p {
+		parts = append(parts, param.flag+"="+strings.Join(param.values, " "))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set implements flag.Value
+func (p *sweepParams) Set(s string) error {
+	idx := strings.Index(s, "=")
+	if idx < 1 {
+		return fmt.Errorf("expected flag=values, got %q", s)
+	}
+	param := sweepParam{flag: strings.TrimPrefix(s[:idx], "-")}
+	for _, value := range strings.Fields(s[idx+1:]) {
+		values, err := expandRange(value)
+		if err != nil {
+			return err
+		}
+		param.values = append(param.values, values...)
+	}
+	if len(param.values) == 0 {
+		return fmt.Errorf("no values to try for %s", param.flag)
+	}
+	*p = append(*p, param)
+	return nil
+}
+
+// expandRange turns from:to:step into the values of the range. Other values, like name:population:selection
+// for -species, are returned as they are
+func expandRange(value string) ([]string, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return []string{value}, nil
+	}
+	var numbers [3]float64
+	for idx, part := range parts {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return []string{value}, nil
+		}
+		numbers[idx] = n
+	}
+	from, to, step := numbers[0], numbers[1], numbers[2]
+	if step <= 0 || to < from {
+		return nil, fmt.Errorf("the range %s must go up in positive steps", value)
+	}
+	var result []string
+	// the small margin keeps rounding from losing the last value of ranges like 0:1:0.1
+	n := int(math.Floor((to-from)/step + 1e-9))
+	for i := 0; i <= n; i++ {
+		v := from + float64(i)*step
+		result = append(result, strconv.FormatFloat(math.Round(v*1e9)/1e9, 'f', -1, 64))
+	}
+	return result, nil
+}
+
+// combinations returns every combination of values of the parameters
+func (p sweepParams) combinations() [][]string {
+	result := [][]string{nil}
+	for _, param := range p {
+		var next [][]string
+		for _, combination := range result {
+			for _, value := range param.values {
+				next = append(next, append(append([]string(nil), combination...), value))
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// args returns the flags of a run: the base flags first, so the values of the sweep win over them
+func (p sweepParams) args(base []string, run sweepRun, generations int) []string {
+	args := append([]string(nil), base...)
+	for idx, param := range p {
+		args = append(args, "-"+param.flag, run.values[idx])
+	}
+	return append(args, "-generations", strconv.Itoa(generations), "-seed", strconv.FormatInt(run.seed, 10), "-headless")
+}
+
+// checkUnshared returns an error if the base flags or the parameters of a sweep use any of the unsharedFlags
+func checkUnshared(params sweepParams, base []string) error {
+	names := map[string]bool{}
+	for _, param := range params {
+		names[param.flag] = true
+	}
+	for _, arg := range base {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		if idx := strings.Index(name, "="); idx >= 0 {
+			name = name[:idx]
+		}
+		names[name] = true
+	}
+	for _, name := range unsharedFlags {
+		if names[name] {
+			return fmt.Errorf("the runs of a sweep can't share -%s", name)
+		}
+	}
+	return nil
+}
+
+// summarize works out the result of a run from its stats
+func summarize(history []biosim.GenerationStats, threshold float64) sweepResult {
+	result := sweepResult{generations: len(history), timeToThreshold: -1}
+	for _, stats := range history {
+		rate := 0.0
+		if stats.Population > 0 {
+			rate = float64(stats.Survivors) / float64(stats.Population)
+		}
+		if result.timeToThreshold < 0 && rate >= threshold {
+			result.timeToThreshold = stats.Generation
+		}
+		result.finalSurvival = rate
+	}
+	return result
+}
+
+// sweepTool runs the simulation for every combination of the parameters, several times each, and writes a table
+// with the outcome of every run. Flags after -- are the base config every run starts from. Runs happen at the same
+// time, so flags that write files or listen on an address are refused
+func sweepTool(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("sweep", flag.ContinueOnError)
+	var params sweepParams
+	flags.Var(&params, "vary", "flag=values to try, like 'mutation-rate=10 50 100' or 'mutation-rate=10:100:10'. can be repeated")
+	replicates := flags.Int("replicates", 3, "number of runs of every combination, each with its own seed")
+	seed := flags.Int64("seed", 1, "seed of the first replicate. the others count up from it")
+	generations := flags.Int("generations", SWEEP_GENERATIONS, "number of generations of every run")
+	threshold := flags.Float64("threshold", 0.5, "survival rate to measure the time to")
+	parallel := flags.Int("parallel", runtime.NumCPU(), "number of runs at the same time")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if len(params) == 0 || *replicates < 1 || *parallel < 1 {
+		return fmt.Errorf("usage: gobiosim sweep -vary flag=values [-vary ...] [-replicates n] [-generations n] [-threshold rate] [-parallel n] [-- base flags]")
+	}
+
+	base := flags.Args()
+	if err := checkUnshared(params, base); err != nil {
+		return err
+	}
+	// runs at the same time share the cpus. flags in the base config override this
+	base = append([]string{"-workers", strconv.Itoa(max(1, runtime.NumCPU() / *parallel))}, base...)
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return sweep(params, base, *replicates, *seed, *generations, *threshold, *parallel, processRunner(exe), out)
+}
+
+// sweep runs the combinations and writes the results table, one row per run, in the order of the runs
+func sweep(params sweepParams, base []string, replicates int, seed int64, generations int, threshold float64, parallel int, run runner, out io.Writer) error {
+	var runs []sweepRun
+	for _, values := range params.combinations() {
+		for replicate := 0; replicate < replicates; replicate++ {
+			runs = append(runs, sweepRun{values: values, replicate: replicate, seed: seed + int64(replicate)})
+		}
+	}
+
+	results := make([]sweepResult, len(runs))
+	errs := make([]error, len(runs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range next {
+				history, err := run(params.args(base, runs[idx], generations))
+				if err != nil {
+					errs[idx] = err
+					continue
+				}
+				results[idx] = summarize(history, threshold)
+			}
+		}()
+	}
+	for idx := range runs {
+		next <- idx
+	}
+	close(next)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	var header []string
+	for _, param := range params {
+		header = append(header, param.flag)
+	}
+	header = append(header, "replicate", "seed", "generations", "final_survival", "time_to_threshold")
+	if _, err := fmt.Fprintln(out, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	for idx, run := range runs {
+		result := results[idx]
+		row := append(append([]string(nil), run.values...),
+			strconv.Itoa(run.replicate),
+			strconv.FormatInt(run.seed, 10),
+			strconv.Itoa(result.generations),
+			strconv.FormatFloat(result.finalSurvival, 'f', 4, 64),
+			strconv.Itoa(result.timeToThreshold))
+		if _, err := fmt.Fprintln(out, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processRunner runs the simulation as a separate process of the executable, so a run that crashes or
+// leaks doesn't take the sweep down with it
+func processRunner(exe string) runner {
+	return func(args []string) ([]biosim.GenerationStats, error) {
+		dir, err := os.MkdirTemp("", "gobiosim-sweep")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		statsFile := filepath.Join(dir, "stats.json")
+		cmd := exec.Command(exe, append(args, "-stats-file", statsFile)...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("%s: %w\n%s", strings.Join(args, " "), err, stderr.String())
+		}
+
+		data, err := os.ReadFile(statsFile)
+		if err != nil {
+			return nil, err
+		}
+		var history []biosim.GenerationStats
+		if err := json.Unmarshal(data, &history); err != nil {
+			return nil, err
+		}
+		return history, nil
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/systay/gobiosim/biosim"
+)
+
+func TestSweepParams(t *testing.T) {
+	var params sweepParams
+	require.NoError(t, params.Set("mutation-rate=10:30:10 100"))
+	require.NoError(t, params.Set("-immigration-rate=0:0.3:0.1"))
+	require.NoError(t, params.Set("species=peeps:500:area peeps:1000:area"))
+	assert.Equal(t, sweepParams{
+		{flag: "mutation-rate", values: []string{"10", "20", "30", "100"}},
+		{flag: "immigration-rate", values: []string{"0", "0.1", "0.2", "0.3"}},
+		{flag: "species", values: []string{"peeps:500:area", "peeps:1000:area"}},
+	}, params)
+	assert.Len(t, params.combinations(), 4*4*2)
+
+	assert.Error(t, params.Set("mutation-rate"))
+	assert.Error(t, params.Set("mutation-rate="))
+	assert.Error(t, params.Set("mutation-rate=30:10:10"))
+}
+
+func TestSummarize(t *testing.T) {
+	history := []biosim.GenerationStats{
+		{Generation: 0, Population: 100, Survivors: 10},
+		{Generation: 1, Population: 100, Survivors: 60},
+		{Generation: 2, Population: 100, Survivors: 40},
+	}
+	assert.Equal(t, sweepResult{generations: 3, finalSurvival: 0.4, timeToThreshold: 1}, summarize(history, 0.5))
+	assert.Equal(t, -1, summarize(history, 0.9).timeToThreshold)
+}
+
+func TestSweep(t *testing.T) {
+	params := sweepParams{{flag: "mutation-rate", values: []string{"10", "20"}}}
+	// the fake simulation lets as many survive as the mutation rate plus the seed
+	run := func(args []string) ([]biosim.GenerationStats, error) {
+		joined := strings.Join(args, " ")
+		assert.True(t, strings.HasPrefix(joined, "-species x:100:area -mutation-rate "), joined)
+		var rate, generations int
+		var seed int64
+		_, err := fmt.Sscanf(strings.TrimPrefix(joined, "-species x:100:area "), "-mutation-rate %d -generations %d -seed %d -headless", &rate, &generations, &seed)
+		require.NoError(t, err)
+		return []biosim.GenerationStats{{Generation: 0, Population: 100, Survivors: rate + int(seed)}}, nil
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, sweep(params, []string{"-species", "x:100:area"}, 2, 5, 10, 0.2, 3, run, &out))
+	assert.Equal(t, `mutation-rate	replicate	seed	generations	final_survival	time_to_threshold
+10	0	5	1	0.1500	-1
+10	1	6	1	0.1600	-1
+20	0	5	1	0.2500	0
+20	1	6	1	0.2600	0
+`, out.String())
+
+	failing := func(args []string) ([]biosim.GenerationStats, error) {
+		return nil, fmt.Errorf("boom")
+	}
+	assert.EqualError(t, sweep(params, nil, 1, 1, 10, 0.5, 2, failing, &out), "boom")
+}
+
+func TestCheckUnshared(t *testing.T) {
+	params := sweepParams{{flag: "mutation-rate", values: []string{"10"}}}
+	assert.NoError(t, checkUnshared(params, []string{"-species", "x:100:area", "-workers", "2"}))
+	assert.EqualError(t, checkUnshared(params, []string{"-lineage-log", "births.jsonl"}), "the runs of a sweep can't share -lineage-log")
+	assert.Error(t, checkUnshared(params, []string{"--checkpoint-dir=checkpoints"}))
+	assert.Error(t, checkUnshared(sweepParams{{flag: "http", values: []string{":8080"}}}, nil))
+
+	assert.Error(t, sweepTool([]string{"-vary", "mutation-rate=10", "--", "-stats-file", "stats.json"}, io.Discard))
+}
+
+func TestProcessRunnerSameSeedSameResult(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the simulation")
+	}
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	// the test binary runs main instead of the tests, see TestMain
+	t.Setenv(RUN_MAIN, "1")
+
+	run := processRunner(exe)
+	args := []string{"-generations", "3", "-seed", "7", "-headless"}
+	first, err := run(append([]string{"-workers", "4"}, args...))
+	require.NoError(t, err)
+	require.Len(t, first, 3)
+	again, err := run(append([]string{"-workers", "4"}, args...))
+	require.NoError(t, err)
+	assert.Equal(t, first, again)
+	alone, err := run(append([]string{"-workers", "1"}, args...))
+	require.NoError(t, err)
+	assert.Equal(t, first, alone)
+}